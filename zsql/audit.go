@@ -0,0 +1,287 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"time"
+)
+
+type (
+	Audited interface {
+		Model
+		AuditKey() string
+	}
+
+	AuditChange struct {
+		Old interface{} `json:"old"`
+		New interface{} `json:"new"`
+	}
+
+	AuditRecord struct {
+		Actor     string
+		Table     string
+		Key       string
+		Action    string
+		Changes   map[string]AuditChange
+		CreatedAt time.Time
+
+		table string
+	}
+
+	AuditOrm struct {
+		Litorm
+		DB    DB
+		Table string
+	}
+)
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, contextKeyActor, actor)
+}
+
+func ActorFrom(ctx context.Context) (actor string) {
+	actor, _ = ctx.Value(contextKeyActor).(string)
+	return
+}
+
+func (r *AuditRecord) TableName() string { return r.table }
+
+func (r *AuditRecord) FieldMapping(dst map[string]interface{}) {
+	dst["actor"] = &r.Actor
+	dst["table_name"] = &r.Table
+	dst["record_key"] = &r.Key
+	dst["action"] = &r.Action
+	dst["changes"] = JSON(&r.Changes)
+	dst["created_at"] = &r.CreatedAt
+}
+
+// fieldValue returns comparable value of FieldMapping element
+func fieldValue(v interface{}) interface{} {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			return value
+		}
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() == reflect.Ptr {
+		return nil
+	}
+	return rv.Interface()
+}
+
+func copyModel(model Model) Model {
+	rv := reflect.ValueOf(model)
+	if rv.Kind() != reflect.Ptr {
+		return model
+	}
+	cp := reflect.New(rv.Type().Elem())
+	cp.Elem().Set(rv.Elem())
+	return cp.Interface().(Model)
+}
+
+func diffModels(before, after Model, fields []string) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+	bm, am := make(FieldMapping, len(fields)), make(FieldMapping, len(fields))
+	if before != nil {
+		before.FieldMapping(bm)
+	}
+	if after != nil {
+		after.FieldMapping(am)
+	}
+	for _, field := range fields {
+		ov, nv := fieldValue(bm[field]), fieldValue(am[field])
+		if !reflect.DeepEqual(ov, nv) {
+			changes[field] = AuditChange{Old: ov, New: nv}
+		}
+	}
+	return changes
+}
+
+func (orm AuditOrm) session() Litorm {
+	session := orm.Litorm
+	if _, ok := session.Conn.(sessionConn); !ok {
+		conn := session.Conn
+		session.Conn = SessionConn(orm.DB, func(Conn) Conn { return conn })
+	}
+	return session
+}
+
+func (orm AuditOrm) lockCondition(condition string) string {
+	if orm.Dialect == SQLite {
+		return condition
+	}
+	return condition + " FOR UPDATE"
+}
+
+func (orm AuditOrm) record(ctx context.Context, session Litorm, model Audited, action string, changes map[string]AuditChange) (err error) {
+	if len(changes) == 0 {
+		return
+	}
+	_, err = session.Insert(ctx, false, &AuditRecord{
+		table:     orm.Table,
+		Actor:     ActorFrom(ctx),
+		Table:     model.TableName(),
+		Key:       model.AuditKey(),
+		Action:    action,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}, nil)
+	return
+}
+
+// load rows matched by condition locked for update
+func (orm AuditOrm) befores(ctx context.Context, session Litorm, model Model, condition string, args []interface{}) ([]Model, error) {
+	list := &modelSlice{new: func() Model { return copyModel(model) }}
+	err := session.Selects(ctx, list, nil, append([]interface{}{orm.lockCondition(condition)}, args...)...)
+	return list.models, err
+}
+
+// Update writes one audit record for each row matched by condition.
+func (orm AuditOrm) Update(ctx context.Context, model Model, fields []string, condition string, args ...interface{}) (result sql.Result, err error) {
+	if _, ok := model.(Audited); !ok {
+		return orm.Litorm.Update(ctx, model, fields, condition, args...)
+	}
+
+	// changed fields of tracked model are resolved before all fields are mapped
+	if len(fields) == 0 && tracking(model) {
+		if fields = Changed(model); len(fields) == 0 {
+			return rowsResult{}, nil
+		}
+	}
+	session := orm.session()
+	FieldMapping{}.MapFields(model, &fields)
+
+	err = WithSessionTx(ctx, orm.DB, func(ctx context.Context) (err error) {
+		befores, err := orm.befores(ctx, session, model, condition, args)
+		if err != nil {
+			return
+		} else if result, err = session.Update(ctx, model, fields, condition, args...); err != nil {
+			return
+		}
+		for _, before := range befores {
+			if err = orm.record(ctx, session, before.(Audited), "update", diffModels(before, model, fields)); err != nil {
+				return
+			}
+		}
+		return
+	})
+	return
+}
+
+// Delete writes one audit record for each row matched by condition.
+func (orm AuditOrm) Delete(ctx context.Context, model Model, condition string, args ...interface{}) (result sql.Result, err error) {
+	if _, ok := model.(Audited); !ok {
+		return orm.Litorm.Delete(ctx, model, condition, args...)
+	}
+
+	session := orm.session()
+	var fields []string
+	FieldMapping{}.MapFields(model, &fields)
+
+	err = WithSessionTx(ctx, orm.DB, func(ctx context.Context) (err error) {
+		befores, err := orm.befores(ctx, session, model, condition, args)
+		if err != nil {
+			return
+		} else if result, err = session.Delete(ctx, model, condition, args...); err != nil {
+			return
+		}
+		for _, before := range befores {
+			if err = orm.record(ctx, session, before.(Audited), "delete", diffModels(before, nil, fields)); err != nil {
+				return
+			}
+		}
+		return
+	})
+	return
+}
+
+func (orm AuditOrm) DeleteByKey(ctx context.Context, model Model) (result sql.Result, err error) {
+	condition, args, err := orm.keyCondition(model)
+	if err != nil {
+		return
+	}
+	return orm.Delete(ctx, model, condition, args...)
+}
+
+func (orm AuditOrm) Save(ctx context.Context, model Model, fields []string) (result sql.Result, err error) {
+	audited, ok := model.(Audited)
+	if !ok {
+		return orm.Litorm.Save(ctx, model, fields)
+	}
+
+	condition, args, err := orm.keyCondition(model)
+	if err != nil {
+		return
+	}
+
+	session := orm.session()
+	FieldMapping{}.MapFields(model, &fields)
+
+	err = WithSessionTx(ctx, orm.DB, func(ctx context.Context) (err error) {
+		befores, err := orm.befores(ctx, session, model, condition, args)
+		if err != nil {
+			return
+		} else if result, err = session.Save(ctx, model, fields); err != nil {
+			return
+		} else if len(befores) == 0 {
+			return orm.record(ctx, session, audited, "insert", diffModels(nil, model, fields))
+		}
+		return orm.record(ctx, session, befores[0].(Audited), "update", diffModels(befores[0], model, fields))
+	})
+	return
+}
+
+func (orm AuditOrm) Updates(ctx context.Context, models ModelIterator, keyFields, fields []string) (result sql.Result, err error) {
+	var audited []Model
+	models.Iterate(func(v interface{}, alloc bool) (next bool) {
+		if model, ok := v.(Audited); ok && !alloc {
+			audited = append(audited, model)
+			return true
+		}
+		return false
+	})
+	if len(audited) == 0 {
+		return orm.Litorm.Updates(ctx, models, keyFields, fields)
+	}
+
+	if len(fields) == 0 {
+		FieldMapping{}.MapFields(audited[0], &fields)
+		fields = excludeStrings(fields, keyFields)
+	}
+	session := orm.session()
+
+	err = WithSessionTx(ctx, orm.DB, func(ctx context.Context) (err error) {
+		befores := make([]Model, len(audited))
+		for i, model := range audited {
+			condition, args, err := orm.keysCondition(model, keyFields)
+			if err != nil {
+				return err
+			}
+			list, err := orm.befores(ctx, session, model, condition, args)
+			if err != nil {
+				return err
+			} else if len(list) > 0 {
+				befores[i] = list[0]
+			}
+		}
+
+		list := &modelSlice{new: func() Model { return copyModel(audited[0]) }, models: audited}
+		if result, err = session.Updates(ctx, list, keyFields, fields); err != nil {
+			return
+		}
+		for i, before := range befores {
+			if before != nil {
+				if err = orm.record(ctx, session, before.(Audited), "update", diffModels(before, audited[i], fields)); err != nil {
+					return
+				}
+			}
+		}
+		return
+	})
+	return
+}
@@ -0,0 +1,137 @@
+package zsql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+type auditT struct{ T }
+
+func (t *auditT) AuditKey() string { return t.FieldA }
+
+func TestAuditUpdate(t *testing.T) {
+	capture, orm := newAuditOrm([]interface{}{"a", "old"})
+
+	ctx := zsql.WithActor(ctx, "admin")
+	if _, err := orm.Update(ctx, &auditT{T{FieldA: "a", FieldB: "new"}}, nil, "WHERE `field_a` = ?", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	captured := capture.Captured()
+	if len(captured) != 5 || !strings.HasSuffix(captured[1].Statement, "FOR UPDATE") {
+		t.Fatal(capture.Render())
+	}
+	if record := captured[3]; !strings.HasPrefix(record.Statement, "INSERT INTO `audit`") ||
+		record.Args[0] != "update" || record.Args[1] != "admin" ||
+		record.Args[2] != `{"field_b":{"old":"old","new":"new"}}` {
+		t.Fatal(capture.Render())
+	}
+}
+
+type auditKeyT struct{ auditT }
+
+func (t *auditKeyT) PrimaryKey() []string { return []string{"field_a"} }
+
+type auditsT []auditKeyT
+
+func (s auditsT) Iterate(f func(v interface{}, alloc bool) (next bool)) {
+	for i := range s {
+		if !f(&s[i], false) {
+			return
+		}
+	}
+	f(&auditKeyT{}, true)
+}
+
+func newAuditOrm(rows ...[]interface{}) (*zsql.Capture, zsql.AuditOrm) {
+	capture := zsql.NewCapture(zsql.MySQL, zsql.SelectResult([]string{"field_a", "field_b"}, rows...))
+	db := capture.DB()
+	return capture, zsql.AuditOrm{Litorm: zsql.Litorm{Conn: db}, DB: db, Table: "audit"}
+}
+
+func auditRecords(capture *zsql.Capture) (records [][]interface{}) {
+	for _, c := range capture.Captured() {
+		if strings.HasPrefix(c.Statement, "INSERT INTO `audit`") {
+			records = append(records, c.Args)
+		}
+	}
+	return
+}
+
+func TestAuditRows(t *testing.T) {
+	capture, orm := newAuditOrm([]interface{}{"a", "old"}, []interface{}{"b", "old"})
+	if _, err := orm.Update(ctx, &auditT{T{FieldB: "new"}}, []string{"field_b"}, "WHERE `field_b` = ?", "old"); err != nil {
+		t.Fatal(err)
+	}
+	if records := auditRecords(capture); len(records) != 2 || records[0][4] != "a" || records[1][4] != "b" ||
+		records[1][2] != `{"field_b":{"old":"old","new":"new"}}` {
+		t.Fatal(capture.Render())
+	}
+
+	capture.Reset()
+	if _, err := orm.Delete(ctx, &auditT{}, "WHERE `field_b` = ?", "old"); err != nil {
+		t.Fatal(err)
+	}
+	if records := auditRecords(capture); len(records) != 2 || records[0][0] != "delete" || records[1][4] != "b" {
+		t.Fatal(capture.Render())
+	}
+}
+
+func TestAuditKeys(t *testing.T) {
+	capture, orm := newAuditOrm([]interface{}{"a", "old"})
+	model := &auditKeyT{auditT{T{FieldA: "a", FieldB: "new"}}}
+
+	if _, err := orm.DeleteByKey(ctx, model); err != nil {
+		t.Fatal(err)
+	} else if records := auditRecords(capture); len(records) != 1 || records[0][0] != "delete" || records[0][4] != "a" {
+		t.Fatal(capture.Render())
+	}
+
+	capture.Reset()
+	if _, err := orm.Save(ctx, model, nil); err != nil {
+		t.Fatal(err)
+	} else if records := auditRecords(capture); len(records) != 1 || records[0][0] != "update" ||
+		records[0][2] != `{"field_b":{"old":"old","new":"new"}}` {
+		t.Fatal(capture.Render())
+	}
+
+	capture, orm = newAuditOrm()
+	if _, err := orm.Save(ctx, model, nil); err != nil {
+		t.Fatal(err)
+	} else if records := auditRecords(capture); len(records) != 1 || records[0][0] != "insert" {
+		t.Fatal(capture.Render())
+	}
+
+	capture, orm = newAuditOrm([]interface{}{"a", "old"})
+	if _, err := orm.Updates(ctx, auditsT{*model}, []string{"field_a"}, nil); err != nil {
+		t.Fatal(err)
+	} else if records := auditRecords(capture); len(records) != 1 || records[0][0] != "update" || records[0][4] != "a" {
+		t.Fatal(capture.Render())
+	}
+}
+
+type trackedAuditT struct {
+	auditT
+	zsql.Tracking
+}
+
+func TestAuditTracked(t *testing.T) {
+	capture, orm := newAuditOrm([]interface{}{"a", "old"})
+	model := &trackedAuditT{auditT: auditT{T{FieldA: "a", FieldB: "old"}}}
+	zsql.Snapshot(model)
+
+	if result, err := orm.Update(ctx, model, nil, "WHERE `field_a` = ?", "a"); err != nil {
+		t.Fatal(err)
+	} else if n, _ := result.RowsAffected(); n != 0 || len(capture.Captured()) != 0 {
+		t.Fatal(capture.Render())
+	}
+
+	model.FieldB = "new"
+	if _, err := orm.Update(ctx, model, nil, "WHERE `field_a` = ?", "a"); err != nil {
+		t.Fatal(err)
+	} else if captured := capture.Captured(); len(captured) != 5 || captured[2].Statement != "UPDATE `test` SET `field_b` = ? WHERE `field_a` = ?" {
+		t.Fatal(capture.Render())
+	}
+}
@@ -0,0 +1,93 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrAutoIDMismatch = errors.New("auto id rows mismatch")
+
+type (
+	AutoID interface {
+		AutoIDField() string
+		SetAutoID(id int64)
+	}
+
+	rowsResult struct {
+		lastInsertId int64
+		rowsAffected int64
+	}
+)
+
+func (r rowsResult) LastInsertId() (int64, error) { return r.lastInsertId, nil }
+
+func (r rowsResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+func (orm Litorm) insertsID(ctx context.Context, bd *SqlBuilder, models ModelIterator, args []interface{}) (result sql.Result, err error) {
+	var ids []AutoID
+	models.Iterate(func(v interface{}, alloc bool) (next bool) {
+		if id, ok := v.(AutoID); ok && !alloc {
+			ids = append(ids, id)
+			return true
+		}
+		return false
+	})
+
+	if len(ids) == 0 {
+		return orm.exec(ctx, bd, args)
+	} else if bd.Dialect == MySQL {
+		return orm.insertsLastID(ctx, bd, args, ids)
+	}
+
+	bd.WriteString(" RETURNING ")
+	bd.quote(ids[0].AutoIDField())
+
+	rows, err := orm.query(ctx, bd, args)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	values := make([]int64, 0, len(ids))
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return
+		}
+		values = append(values, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return
+	} else if len(values) != len(ids) {
+		return rowsResult{rowsAffected: int64(len(values))}, ErrAutoIDMismatch
+	}
+
+	for i, id := range ids {
+		id.SetAutoID(values[i])
+	}
+	return rowsResult{lastInsertId: values[len(values)-1], rowsAffected: int64(len(values))}, nil
+}
+
+func (orm Litorm) insertsLastID(ctx context.Context, bd *SqlBuilder, args []interface{}, ids []AutoID) (result sql.Result, err error) {
+	if result, err = orm.exec(ctx, bd, args); err != nil {
+		return
+	}
+
+	first, err := result.LastInsertId()
+	if err != nil {
+		return
+	}
+
+	if n, e := result.RowsAffected(); e != nil {
+		return result, e
+	} else if n != int64(len(ids)) {
+		return result, ErrAutoIDMismatch
+	}
+
+	for i, id := range ids {
+		id.SetAutoID(first + int64(i))
+	}
+	return
+}
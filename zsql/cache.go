@@ -0,0 +1,161 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-zing/gozz-kit/zstore"
+)
+
+type (
+	cachedModel struct {
+		model  Model
+		fields []string
+	}
+
+	cachedModels struct {
+		models ModelIterator
+		fields []string
+	}
+)
+
+// values of scanner fields are encoded as type tagged driver values, so that Scan receives original driver types
+func marshalModel(model Model, fields []string) (map[string]interface{}, error) {
+	mapping := make(FieldMapping, len(fields))
+	mapping.MapFields(model, &fields)
+	values := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if v, ok := mapping[field]; !ok {
+			continue
+		} else if v = convertPtr(v); !isScanner(v) {
+			values[field] = v
+		} else if value, err := driver.DefaultParameterConverter.ConvertValue(v); err != nil {
+			return nil, fmt.Errorf("cache field %s: %w", field, err)
+		} else {
+			values[field] = RecordValue{Value: value}
+		}
+	}
+	return values, nil
+}
+
+func isScanner(v interface{}) (ok bool) {
+	_, ok = v.(sql.Scanner)
+	return
+}
+
+func unmarshalModel(model Model, values map[string]json.RawMessage) (err error) {
+	mapping := make(FieldMapping, len(values))
+	model.FieldMapping(mapping)
+	for field, raw := range values {
+		switch dst := convertPtr(mapping[field]).(type) {
+		case nil:
+		case sql.Scanner:
+			var v RecordValue
+			if err = json.Unmarshal(raw, &v); err == nil {
+				err = dst.Scan(v.Value)
+			}
+		default:
+			err = json.Unmarshal(raw, dst)
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (c cachedModel) MarshalJSON() ([]byte, error) {
+	values, err := marshalModel(c.model, c.fields)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(values)
+}
+
+func (c cachedModel) UnmarshalJSON(data []byte) (err error) {
+	values := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &values); err == nil {
+		err = unmarshalModel(c.model, values)
+	}
+	return
+}
+
+func (c cachedModels) MarshalJSON() (data []byte, err error) {
+	list := make([]map[string]interface{}, 0)
+	c.models.Iterate(func(v interface{}, alloc bool) (next bool) {
+		if model, ok := v.(Model); ok && !alloc {
+			values, e := marshalModel(model, c.fields)
+			list, err = append(list, values), e
+			return err == nil
+		}
+		return false
+	})
+	if err != nil {
+		return
+	}
+	return json.Marshal(list)
+}
+
+func (c cachedModels) UnmarshalJSON(data []byte) (err error) {
+	var list []map[string]json.RawMessage
+	if err = json.Unmarshal(data, &list); err != nil {
+		return
+	}
+	i := 0
+	c.models.Iterate(func(v interface{}, alloc bool) (next bool) {
+		if model, ok := v.(Model); !ok {
+			err = ErrInvalidModelsIterator
+		} else if i < len(list) {
+			err, i = unmarshalModel(model, list[i]), i+1
+			return err == nil
+		}
+		return false
+	})
+	return
+}
+
+func (orm Litorm) SelectCached(ctx context.Context, store zstore.Store, key string, ttl time.Duration, model Model, fields []string, ext ...interface{}) error {
+	FieldMapping{}.MapFields(model, &fields)
+	return zstore.WithCache(ctx, key, func() (v interface{}, exp time.Duration, err error) {
+		if err = orm.Select(ctx, model, fields, ext...); err != nil {
+			return
+		}
+		return cachedModel{model: model, fields: fields}, ttl, nil
+	}, store, &cachedModel{model: model, fields: fields})
+}
+
+func (orm Litorm) SelectsCached(ctx context.Context, store zstore.Store, key string, ttl time.Duration, models ModelIterator, fields []string, ext ...interface{}) error {
+	return zstore.WithCache(ctx, key, func() (v interface{}, exp time.Duration, err error) {
+		if err = orm.Selects(ctx, models, fields, ext...); err != nil {
+			return
+		}
+		return cachedModels{models: models, fields: fields}, ttl, nil
+	}, store, &cachedModels{models: models, fields: fields})
+}
+
+// InvalidateCache deletes keys from store after session transaction of db committed,
+// or immediately without session transaction.
+func InvalidateCache(ctx context.Context, db DB, store zstore.Store, keys ...string) error {
+	del := func(ctx context.Context) error {
+		var errs []string
+		for _, key := range keys {
+			if err := store.Del(ctx, key); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		return errors.New(strings.Join(errs, ". "))
+	}
+	if OnCommit(ctx, db, del) {
+		return nil
+	}
+	return del(ctx)
+}
@@ -0,0 +1,112 @@
+package zsql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+type memStore struct{ sync.Map }
+
+func (s *memStore) Get(ctx context.Context, key string) ([]byte, error) {
+	v, _ := s.Load(key)
+	b, _ := v.([]byte)
+	return b, nil
+}
+
+func (s *memStore) Set(ctx context.Context, key string, value []byte, exp time.Duration) error {
+	s.Store(key, value)
+	return nil
+}
+
+func (s *memStore) Del(ctx context.Context, key string) error {
+	s.Delete(key)
+	return nil
+}
+
+func TestSelectsCached(t *testing.T) {
+	capture := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{Columns: []string{"field_a", "field_b"}, Rows: [][]interface{}{{"a", "b"}, {"c", "d"}}}
+	})
+	db := capture.DB()
+	orm := zsql.Litorm{Conn: zsql.SessionConn(db)}
+	store := &memStore{}
+
+	for i := 0; i < 2; i++ {
+		st := &sliceT{}
+		if err := orm.SelectsCached(ctx, store, "key", time.Minute, st, nil); err != nil {
+			t.Fatal(err)
+		} else if len(*st) != 2 || (*st)[1].FieldB != "d" || len(capture.Captured()) != 1 {
+			t.Fatal(*st, capture.Render())
+		}
+	}
+
+	if err := zsql.WithSessionTx(ctx, db, func(ctx context.Context) error {
+		return zsql.InvalidateCache(ctx, db, store, "key")
+	}); err != nil {
+		t.Fatal(err)
+	} else if v, _ := store.Get(ctx, "key"); v != nil {
+		t.Fatal(string(v))
+	}
+}
+
+type numT struct {
+	ID    int64
+	Count sql.NullInt64
+	Rate  sql.NullFloat64
+	At    sql.NullTime
+	Hash  hexT
+}
+
+// hex string stored as binary column
+type hexT string
+
+func (t numT) TableName() string { return "num" }
+
+func (t *numT) FieldMapping(dst map[string]interface{}) {
+	dst["id"] = &t.ID
+	dst["count"] = &t.Count
+	dst["rate"] = &t.Rate
+	dst["at"] = &t.At
+	dst["hash"] = &t.Hash
+}
+
+func TestSelectCachedScanner(t *testing.T) {
+	zsql.RegisterConverter(reflect.TypeOf(hexT("")), zsql.Converter{
+		Value: func(v interface{}) (driver.Value, error) { return hex.DecodeString(string(v.(hexT))) },
+		Scan: func(ptr interface{}, src interface{}) error {
+			b, ok := src.([]byte)
+			if !ok {
+				return fmt.Errorf("invalid hash %T", src)
+			}
+			*ptr.(*hexT) = hexT(hex.EncodeToString(b))
+			return nil
+		},
+	})
+	defer zsql.UnregisterConverter(reflect.TypeOf(hexT("")))
+
+	const large = int64(1<<53 + 1)
+	at := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	capture := zsql.NewCapture(zsql.MySQL, zsql.SelectResult([]string{"at", "count", "hash", "id", "rate"},
+		[]interface{}{at, large, []byte{0xff, 0x00}, int64(1), 0.5}))
+	orm := zsql.Litorm{Conn: capture.DB()}
+	store := &memStore{}
+
+	for i := 0; i < 2; i++ {
+		v := &numT{}
+		if err := orm.SelectCached(ctx, store, "key", time.Minute, v, nil); err != nil {
+			t.Fatal(err)
+		} else if v.Count.Int64 != large || !v.Count.Valid || v.Rate.Float64 != 0.5 || !v.At.Time.Equal(at) ||
+			v.Hash != "ff00" || len(capture.Captured()) != 1 {
+			t.Fatal(v, capture.Render())
+		}
+	}
+}
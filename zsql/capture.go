@@ -0,0 +1,167 @@
+package zsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	Captured struct {
+		Statement string
+		Args      []interface{}
+	}
+
+	CaptureResult struct {
+		Columns      []string
+		ColumnTypes  []string
+		Rows         [][]interface{}
+		LastInsertId int64
+		RowsAffected int64
+		Err          error
+	}
+
+	Capture struct {
+		Dialect Dialect
+		Result  func(statement string, args []interface{}) CaptureResult
+
+		mu       sync.Mutex
+		captured []Captured
+	}
+)
+
+func NewCapture(dialect Dialect, result func(statement string, args []interface{}) CaptureResult) *Capture {
+	return &Capture{Dialect: dialect, Result: result}
+}
+
+// SelectResult returns capture result answering SELECT statements with rows, other statements affect one row.
+func SelectResult(columns []string, rows ...[]interface{}) func(statement string, args []interface{}) CaptureResult {
+	return func(statement string, args []interface{}) CaptureResult {
+		if strings.HasPrefix(statement, "SELECT") {
+			return CaptureResult{Columns: columns, Rows: rows}
+		}
+		return CaptureResult{RowsAffected: 1}
+	}
+}
+
+func (c *Capture) DB() *sql.DB { return openHookDB(c) }
+
+func (c *Capture) Connector() driver.Connector { return hookConnector{handler: c} }
+
+func (c *Capture) Captured() []Captured {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Captured(nil), c.captured...)
+}
+
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	c.captured = nil
+	c.mu.Unlock()
+}
+
+func (c *Capture) Render() string {
+	bd := new(strings.Builder)
+	for _, captured := range c.Captured() {
+		bd.WriteString(c.Dialect.Render(captured.Statement, captured.Args))
+		bd.WriteString(";\n")
+	}
+	return bd.String()
+}
+
+func (c *Capture) capture(statement string, args []interface{}) CaptureResult {
+	c.mu.Lock()
+	c.captured = append(c.captured, Captured{Statement: statement, Args: args})
+	c.mu.Unlock()
+	if c.Result == nil {
+		return CaptureResult{}
+	}
+	return c.Result(statement, args)
+}
+
+func (c *Capture) begin() error { return c.capture("BEGIN", nil).Err }
+
+func (c *Capture) commit() error { return c.capture("COMMIT", nil).Err }
+
+func (c *Capture) rollback() error { return c.capture("ROLLBACK", nil).Err }
+
+func (c *Capture) exec(statement string, args []interface{}) (driver.Result, error) {
+	ret := c.capture(statement, args)
+	if ret.Err != nil {
+		return nil, ret.Err
+	}
+	return rowsResult{lastInsertId: ret.LastInsertId, rowsAffected: ret.RowsAffected}, nil
+}
+
+func (c *Capture) query(statement string, args []interface{}) (driver.Rows, error) {
+	ret := c.capture(statement, args)
+	if ret.Err != nil {
+		return nil, ret.Err
+	}
+	return &hookRows{columns: ret.Columns, types: ret.ColumnTypes, rows: ret.Rows}, nil
+}
+
+// Render interpolates args into statement placeholders for display only.
+func (d Dialect) Render(statement string, args []interface{}) string {
+	bd := new(strings.Builder)
+	last, index := 0, 0
+	_ = d.walkPlaceholders(statement, func(start, end int, name string) error {
+		i := -1
+		if len(name) == 0 {
+			i, index = index, index+1
+		} else if name[0] == '$' {
+			n, _ := strconv.Atoi(name[1:])
+			i = n - 1
+		}
+		if i >= 0 && i < len(args) {
+			bd.WriteString(statement[last:start])
+			d.writeLiteral(bd, args[i])
+			last = end
+		}
+		return nil
+	})
+	bd.WriteString(statement[last:])
+	return bd.String()
+}
+
+func (d Dialect) writeLiteral(bd *strings.Builder, v interface{}) {
+	if converted, err := driver.DefaultParameterConverter.ConvertValue(v); err == nil {
+		v = converted
+	}
+	switch value := v.(type) {
+	case nil:
+		bd.WriteString("NULL")
+	case bool:
+		bd.WriteString(strings.ToUpper(strconv.FormatBool(value)))
+	case int64:
+		bd.WriteString(strconv.FormatInt(value, 10))
+	case float64:
+		bd.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	case []byte:
+		if d == PostgreSQL {
+			bd.WriteString(`'\x` + hex.EncodeToString(value) + `'`)
+		} else {
+			bd.WriteString("X'" + hex.EncodeToString(value) + "'")
+		}
+	case time.Time:
+		d.writeString(bd, value.Format("2006-01-02 15:04:05.999999999"))
+	case string:
+		d.writeString(bd, value)
+	default:
+		d.writeString(bd, fmt.Sprint(value))
+	}
+}
+
+func (d Dialect) writeString(bd *strings.Builder, v string) {
+	if d == MySQL {
+		v = strings.ReplaceAll(v, `\`, `\\`)
+	}
+	bd.WriteRune('\'')
+	bd.WriteString(strings.ReplaceAll(v, "'", "''"))
+	bd.WriteRune('\'')
+}
@@ -0,0 +1,46 @@
+package zsql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestCapture(t *testing.T) {
+	capture := zsql.NewCapture(zsql.MySQL, nil)
+	db := capture.DB()
+	orm := zsql.Litorm{Conn: zsql.SessionConn(db)}
+
+	committed := false
+	err := zsql.WithSessionTx(ctx, db, func(ctx context.Context) error {
+		zsql.OnCommit(ctx, db, func(ctx context.Context) error { committed = true; return nil })
+		_, err := orm.Insert(ctx, false, &T{FieldA: "it's", FieldB: `a\b`}, nil)
+		return err
+	})
+	if err != nil || !committed {
+		t.Fatal(err, committed)
+	}
+
+	want := "BEGIN;\nINSERT INTO `test` (`field_a`,`field_b`) VALUES ('it''s','a\\\\b');\nCOMMIT;\n"
+	if got := capture.Render(); got != want {
+		t.Fatalf("want %q got %q", want, got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	for _, c := range []struct {
+		dialect   zsql.Dialect
+		statement string
+		args      []interface{}
+		want      string
+	}{
+		{zsql.MySQL, "SELECT ? FROM t WHERE a = '?' AND b = ? -- ?", []interface{}{1, "x"}, "SELECT 1 FROM t WHERE a = '?' AND b = 'x' -- ?"},
+		{zsql.PostgreSQL, `SELECT $2::text, $1 FROM "t?" WHERE a::int = $1`, []interface{}{1, []byte("b")}, `SELECT '\x62'::text, 1 FROM "t?" WHERE a::int = 1`},
+		{zsql.SQLite, "SELECT ? /* ? */", []interface{}{nil}, "SELECT NULL /* ? */"},
+	} {
+		if got := c.dialect.Render(c.statement, c.args); got != c.want {
+			t.Fatalf("want %q got %q", c.want, got)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package zsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type (
+	Column interface {
+		sql.Scanner
+		driver.Valuer
+	}
+
+	Codec struct {
+		Marshal   func(interface{}) ([]byte, error)
+		Unmarshal func([]byte, interface{}) error
+	}
+
+	codecColumn struct {
+		v     interface{}
+		codec Codec
+	}
+)
+
+var (
+	JSONCodec = Codec{
+		Marshal:   json.Marshal,
+		Unmarshal: json.Unmarshal,
+	}
+
+	ArrayCodec = Codec{
+		Marshal:   marshalArray,
+		Unmarshal: unmarshalArray,
+	}
+)
+
+func JSON(v interface{}) Column { return JSONCodec.Column(v) }
+
+func StringArray(v *[]string) Column { return ArrayCodec.Column(v) }
+
+func IntArray(v *[]int64) Column { return ArrayCodec.Column(v) }
+
+func (codec Codec) Column(v interface{}) Column { return codecColumn{v: v, codec: codec} }
+
+func (c codecColumn) Value() (driver.Value, error) {
+	if rv := reflect.ValueOf(c.v); !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return nil, nil
+	}
+	data, err := c.codec.Marshal(c.v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c codecColumn) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		if rv := reflect.ValueOf(c.v); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+		}
+		return nil
+	case []byte:
+		return c.codec.Unmarshal(v, c.v)
+	case string:
+		return c.codec.Unmarshal([]byte(v), c.v)
+	default:
+		return fmt.Errorf("unsupported column source type %T", src)
+	}
+}
+
+func marshalArray(v interface{}) ([]byte, error) {
+	bd := new(strings.Builder)
+	bd.WriteRune('{')
+	switch arr := reflect.Indirect(reflect.ValueOf(v)).Interface().(type) {
+	case []string:
+		for i, str := range arr {
+			if i > 0 {
+				bd.WriteRune(',')
+			}
+			bd.WriteRune('"')
+			for _, r := range str {
+				if r == '"' || r == '\\' {
+					bd.WriteRune('\\')
+				}
+				bd.WriteRune(r)
+			}
+			bd.WriteRune('"')
+		}
+	case []int64:
+		for i, n := range arr {
+			if i > 0 {
+				bd.WriteRune(',')
+			}
+			bd.WriteString(strconv.FormatInt(n, 10))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported array type %T", v)
+	}
+	bd.WriteRune('}')
+	return []byte(bd.String()), nil
+}
+
+func parseArray(data []byte) (elements []string, err error) {
+	str := strings.TrimSpace(string(data))
+	if len(str) < 2 || str[0] != '{' || str[len(str)-1] != '}' {
+		return nil, fmt.Errorf("invalid array literal %q", str)
+	} else if str = str[1 : len(str)-1]; len(strings.TrimSpace(str)) == 0 {
+		return []string{}, nil
+	}
+
+	elem := new(strings.Builder)
+	quoted, escaped, wasQuoted := false, false, false
+	for _, r := range str + "," {
+		switch {
+		case escaped:
+			elem.WriteRune(r)
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted, wasQuoted = !quoted, true
+		case r == ',' && !quoted:
+			v := elem.String()
+			if !wasQuoted {
+				if v = strings.TrimSpace(v); strings.EqualFold(v, "NULL") {
+					v = ""
+				}
+			}
+			elements = append(elements, v)
+			elem.Reset()
+			wasQuoted = false
+		default:
+			elem.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated array literal %q", str)
+	}
+	return
+}
+
+func unmarshalArray(data []byte, v interface{}) error {
+	elements, err := parseArray(data)
+	if err != nil {
+		return err
+	}
+	switch arr := v.(type) {
+	case *[]string:
+		*arr = elements
+	case *[]int64:
+		ints := make([]int64, len(elements))
+		for i, elem := range elements {
+			if len(elem) == 0 {
+				continue
+			} else if ints[i], err = strconv.ParseInt(elem, 10, 64); err != nil {
+				return err
+			}
+		}
+		*arr = ints
+	default:
+		return fmt.Errorf("unsupported array type %T", v)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package zsql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestArrayColumn(t *testing.T) {
+	strs := []string{"a", "b,c", `d"e`, ""}
+	v, err := zsql.StringArray(&strs).Value()
+	if err != nil || v != `{"a","b,c","d\"e",""}` {
+		t.Fatal(v, err)
+	}
+
+	var got []string
+	if err = zsql.StringArray(&got).Scan([]byte(v.(string))); err != nil || !reflect.DeepEqual(got, strs) {
+		t.Fatal(got, err)
+	}
+
+	var ints []int64
+	if err = zsql.IntArray(&ints).Scan("{1, 2,NULL,3}"); err != nil || !reflect.DeepEqual(ints, []int64{1, 2, 0, 3}) {
+		t.Fatal(ints, err)
+	}
+}
+
+func TestJSONColumn(t *testing.T) {
+	var m map[string]int
+	if err := zsql.JSON(&m).Scan([]byte(`{"a":1}`)); err != nil || m["a"] != 1 {
+		t.Fatal(m, err)
+	}
+	if err := zsql.JSON(&m).Scan(nil); err != nil || m != nil {
+		t.Fatal(m, err)
+	}
+	if v, err := zsql.JSON((*map[string]int)(nil)).Value(); err != nil || v != nil {
+		t.Fatal(v, err)
+	}
+}
@@ -0,0 +1,124 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+const (
+	TagRoute     = "route"
+	TagApi       = "api"
+	TagRequestID = "request_id"
+	TagCaller    = "caller"
+)
+
+// LowCardinalityTags are default tags kept on statement cached conn
+// to avoid growing prepared statements cache by per request values.
+var LowCardinalityTags = []string{TagRoute, TagApi, TagCaller}
+
+type CommentConn struct {
+	Conn Conn
+	Keys []string
+}
+
+func WithSQLTags(ctx context.Context, kvs ...string) context.Context {
+	tags := make(map[string]string)
+	for k, v := range SQLTags(ctx) {
+		tags[k] = v
+	}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		tags[kvs[i]] = kvs[i+1]
+	}
+	return context.WithValue(ctx, contextKeySQLTags, tags)
+}
+
+func SQLTags(ctx context.Context) (tags map[string]string) {
+	tags, _ = ctx.Value(contextKeySQLTags).(map[string]string)
+	return
+}
+
+func NewCommentConn(conn Conn, keys ...string) *CommentConn {
+	if _, cached := conn.(*StmtCacheConn); cached && len(keys) == 0 {
+		keys = LowCardinalityTags
+	}
+	return &CommentConn{Conn: conn, Keys: keys}
+}
+
+func Commenter(keys ...string) func(Conn) Conn {
+	return func(conn Conn) Conn { return NewCommentConn(conn, keys...) }
+}
+
+func caller() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if fn := frame.Function; !strings.Contains(fn, "gozz-kit/zsql.") && !strings.HasPrefix(fn, "database/sql.") {
+			return fn
+		} else if !more {
+			return ""
+		}
+	}
+}
+
+func (cc *CommentConn) allowed(key string) bool {
+	return len(cc.Keys) == 0 || containsString(cc.Keys, key)
+}
+
+func (cc *CommentConn) comment(ctx context.Context, statement string) string {
+	tags := make(map[string]string)
+	for k, v := range SQLTags(ctx) {
+		if cc.allowed(k) {
+			tags[k] = v
+		}
+	}
+	if _, exist := tags[TagCaller]; !exist && cc.allowed(TagCaller) {
+		if fn := caller(); len(fn) > 0 {
+			tags[TagCaller] = fn
+		}
+	}
+	if len(tags) == 0 {
+		return statement
+	}
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	bd := new(strings.Builder)
+	bd.WriteString(statement)
+	bd.WriteString(" /*")
+	for i, k := range keys {
+		if i > 0 {
+			bd.WriteRune(',')
+		}
+		bd.WriteString(url.PathEscape(k))
+		bd.WriteString("='")
+		bd.WriteString(url.PathEscape(tags[k]))
+		bd.WriteRune('\'')
+	}
+	bd.WriteString("*/")
+	return bd.String()
+}
+
+func (cc *CommentConn) QueryContext(ctx context.Context, statement string, args ...interface{}) (*sql.Rows, error) {
+	return cc.Conn.QueryContext(ctx, cc.comment(ctx, statement), args...)
+}
+
+func (cc *CommentConn) QueryRowContext(ctx context.Context, statement string, args ...interface{}) *sql.Row {
+	return cc.Conn.QueryRowContext(ctx, cc.comment(ctx, statement), args...)
+}
+
+func (cc *CommentConn) ExecContext(ctx context.Context, statement string, args ...interface{}) (sql.Result, error) {
+	return cc.Conn.ExecContext(ctx, cc.comment(ctx, statement), args...)
+}
+
+func (cc *CommentConn) PrepareContext(ctx context.Context, statement string) (*sql.Stmt, error) {
+	return cc.Conn.PrepareContext(ctx, cc.comment(ctx, statement))
+}
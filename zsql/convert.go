@@ -0,0 +1,76 @@
+package zsql
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// Converter persists values of type not implementing sql.Scanner or driver.Valuer.
+	Converter struct {
+		// convert field value into driver value
+		Value func(v interface{}) (driver.Value, error)
+		// assign scanned driver value into field pointer
+		Scan func(ptr interface{}, src interface{}) error
+	}
+
+	// scan destination and driver arg of registered type field pointer
+	convertValue struct {
+		ptr       reflect.Value
+		converter Converter
+	}
+)
+
+var (
+	converters     sync.Map
+	convertersSize int32
+)
+
+// RegisterConverter registers converter for fields of type, it panics if Value or Scan is nil.
+func RegisterConverter(typ reflect.Type, converter Converter) {
+	if converter.Value == nil || converter.Scan == nil {
+		panic("zsql: RegisterConverter requires both Value and Scan of converter for " + typ.String())
+	}
+	if _, loaded := converters.LoadOrStore(typ, converter); loaded {
+		converters.Store(typ, converter)
+	} else {
+		atomic.AddInt32(&convertersSize, 1)
+	}
+}
+
+// UnregisterConverter removes converter of type.
+func UnregisterConverter(typ reflect.Type) {
+	if _, loaded := converters.LoadAndDelete(typ); loaded {
+		atomic.AddInt32(&convertersSize, -1)
+	}
+}
+
+func convertPtr(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return v
+	} else if converter, ok := converters.Load(rv.Type().Elem()); ok {
+		return convertValue{ptr: rv, converter: converter.(Converter)}
+	}
+	return v
+}
+
+// convert arg of registered type by its value
+func convertArg(v interface{}) interface{} {
+	if v == nil || atomic.LoadInt32(&convertersSize) == 0 {
+		return v
+	} else if converter, ok := converters.Load(reflect.TypeOf(v)); ok {
+		ptr := reflect.New(reflect.TypeOf(v))
+		ptr.Elem().Set(reflect.ValueOf(v))
+		return convertValue{ptr: ptr, converter: converter.(Converter)}
+	}
+	return v
+}
+
+func (c convertValue) Scan(src interface{}) error { return c.converter.Scan(c.ptr.Interface(), src) }
+
+func (c convertValue) Value() (driver.Value, error) {
+	return c.converter.Value(c.ptr.Elem().Interface())
+}
@@ -0,0 +1,134 @@
+package zsql_test
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+type cents int64
+
+type priceT struct {
+	Name  string
+	Price cents
+}
+
+func (t priceT) TableName() string { return "price" }
+
+func (t *priceT) FieldMapping(dst map[string]interface{}) {
+	dst["name"] = &t.Name
+	dst["price"] = &t.Price
+}
+
+func registerCents() {
+	zsql.RegisterConverter(reflect.TypeOf(cents(0)), zsql.Converter{
+		Value: func(v interface{}) (driver.Value, error) {
+			c := v.(cents)
+			return fmt.Sprintf("%d.%02d", c/100, c%100), nil
+		},
+		Scan: func(ptr interface{}, src interface{}) error {
+			units, fraction := fmt.Sprintf("%s", src), ""
+			if i := strings.IndexByte(units, '.'); i >= 0 {
+				units, fraction = units[:i], units[i+1:]
+			}
+			c, err := strconv.ParseInt(units+(fraction + "00")[:2], 10, 64)
+			*ptr.(*cents) = cents(c)
+			return err
+		},
+	})
+}
+
+func TestConverter(t *testing.T) {
+	registerCents()
+	defer zsql.UnregisterConverter(reflect.TypeOf(cents(0)))
+
+	capture := zsql.NewCapture(zsql.MySQL, zsql.SelectResult([]string{"name", "price"},
+		[]interface{}{"a", []byte("3.05")}, []interface{}{"b", []byte("3.5")}))
+	orm := zsql.Litorm{Conn: capture.DB()}
+
+	if _, err := orm.Insert(ctx, false, &priceT{Name: "a", Price: 1250}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := capture.Render(); got != "INSERT INTO `price` (`name`,`price`) VALUES ('a','12.50');\n" {
+		t.Fatal(got)
+	}
+
+	v := &priceT{}
+	if err := orm.Select(ctx, v, nil); err != nil || v.Price != 305 {
+		t.Fatal(v, err)
+	}
+
+	list := &pricesT{}
+	if err := orm.Selects(ctx, list, nil); err != nil || len(*list) != 2 || (*list)[1].Price != 350 {
+		t.Fatal(list, err)
+	}
+
+	capture.Reset()
+	if _, err := orm.Delete(ctx, &priceT{}, "WHERE `price` = :price", zsql.Bind(&priceT{Price: 1250})); err != nil {
+		t.Fatal(err)
+	} else if got := capture.Render(); got != "DELETE FROM `price` WHERE `price` = '12.50';\n" {
+		t.Fatal(got)
+	}
+
+	store := &memStore{}
+	for i := 0; i < 2; i++ {
+		v := &priceT{}
+		if err := orm.SelectCached(ctx, store, "price", time.Minute, v, nil); err != nil || v.Price != 305 {
+			t.Fatal(v, err)
+		}
+	}
+	if raw, _ := store.Get(ctx, "price"); !strings.Contains(string(raw), `"3.05"`) {
+		t.Fatal(string(raw))
+	}
+
+	capture.Reset()
+	bound := zsql.Bind(struct {
+		Price cents `db:"price"`
+	}{Price: 1250})
+	if _, err := orm.Delete(ctx, &priceT{}, "WHERE `price` = :price OR `price` = ?", bound, cents(325)); err != nil {
+		t.Fatal(err)
+	} else if got := capture.Render(); got != "DELETE FROM `price` WHERE `price` = '12.50' OR `price` = '3.25';\n" {
+		t.Fatal(got)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("converter without Scan registered")
+			}
+		}()
+		zsql.RegisterConverter(reflect.TypeOf(cents(0)), zsql.Converter{Value: func(v interface{}) (driver.Value, error) { return v, nil }})
+	}()
+
+	// values are bound as is without converters
+	zsql.UnregisterConverter(reflect.TypeOf(cents(0)))
+	capture.Reset()
+	if _, err := orm.Insert(ctx, false, &priceT{Name: "a", Price: 1250}, nil); err != nil {
+		t.Fatal(err)
+	} else if got := capture.Render(); got != "INSERT INTO `price` (`name`,`price`) VALUES ('a',1250);\n" {
+		t.Fatal(got)
+	}
+}
+
+type pricesT []priceT
+
+func (s *pricesT) Iterate(f func(v interface{}, alloc bool) (next bool)) {
+	for i := 0; ; i++ {
+		if c := i >= len(*s); !c {
+			if !f(&(*s)[i], c) {
+				return
+			}
+		} else if n := append(*s, priceT{}); f(&n[i], c) {
+			*s = n
+		} else {
+			*s = n[:i]
+			return
+		}
+	}
+}
@@ -35,4 +35,10 @@ type (
 		Inserts(ctx context.Context, ignore bool, models ModelIterator, fields []string, ext ...interface{}) (result sql.Result, err error)
 		Update(ctx context.Context, model Model, fields []string, condition string, args ...interface{}) (result sql.Result, err error)
 	}
+
+	// DeleteOrm is separated from Orm to keep existing Orm implementations compatible
+	DeleteOrm interface {
+		Orm
+		Delete(ctx context.Context, model Model, condition string, args ...interface{}) (result sql.Result, err error)
+	}
 )
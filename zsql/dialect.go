@@ -0,0 +1,38 @@
+package zsql
+
+import (
+	"strconv"
+)
+
+type Dialect int
+
+const (
+	MySQL Dialect = iota
+	PostgreSQL
+	SQLite
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case PostgreSQL:
+		return "postgres"
+	case SQLite:
+		return "sqlite"
+	default:
+		return "mysql"
+	}
+}
+
+func (d Dialect) QuoteRune() rune {
+	if d == MySQL {
+		return '`'
+	}
+	return '"'
+}
+
+func (d Dialect) Placeholder(n int) string {
+	if d == PostgreSQL {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
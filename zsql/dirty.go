@@ -0,0 +1,80 @@
+package zsql
+
+import (
+	"reflect"
+	"sort"
+)
+
+type (
+	// Tracked model keeps snapshot of field values loaded from database
+	// so that Update with nil fields writes changed columns only.
+	Tracked interface {
+		SetSnapshot(snapshot map[string]interface{})
+		Snapshot() map[string]interface{}
+	}
+
+	// Tracking implements Tracked by embedding into model struct.
+	Tracking struct{ snapshot map[string]interface{} }
+)
+
+func (t *Tracking) SetSnapshot(snapshot map[string]interface{}) { t.snapshot = snapshot }
+
+func (t *Tracking) Snapshot() map[string]interface{} { return t.snapshot }
+
+func snapshotValue(v interface{}) interface{} {
+	v = fieldValue(v)
+	if b, ok := v.([]byte); ok {
+		return append([]byte(nil), b...)
+	}
+	return v
+}
+
+func snapshot(model Model, mapping FieldMapping, fields []string, merge bool) {
+	tracked, ok := model.(Tracked)
+	if !ok {
+		return
+	}
+	values := make(map[string]interface{}, len(fields))
+	if merge {
+		for k, v := range tracked.Snapshot() {
+			values[k] = v
+		}
+	}
+	for _, field := range fields {
+		if v, ok := mapping[field]; ok {
+			values[field] = snapshotValue(v)
+		}
+	}
+	tracked.SetSnapshot(values)
+}
+
+func tracking(model Model) bool {
+	tracked, ok := model.(Tracked)
+	return ok && tracked.Snapshot() != nil
+}
+
+// Snapshot takes snapshot of all mapped field values of tracked model.
+func Snapshot(model Model) {
+	var fields []string
+	mapping := make(FieldMapping)
+	mapping.MapFields(model, &fields)
+	snapshot(model, mapping, fields, false)
+}
+
+// Changed returns sorted fields of tracked model differ from its snapshot.
+// Fields not in snapshot are not considered.
+func Changed(model Model) (fields []string) {
+	tracked, ok := model.(Tracked)
+	if !ok {
+		return
+	}
+	mapping := make(FieldMapping)
+	model.FieldMapping(mapping)
+	for field, value := range tracked.Snapshot() {
+		if v, ok := mapping[field]; ok && !reflect.DeepEqual(snapshotValue(v), value) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return
+}
@@ -0,0 +1,41 @@
+package zsql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+type trackedT struct {
+	T
+	zsql.Tracking
+}
+
+func TestDirtyUpdate(t *testing.T) {
+	capture := zsql.NewCapture(zsql.MySQL, zsql.SelectResult([]string{"field_a", "field_b"}, []interface{}{"a", "b"}))
+	orm := zsql.Litorm{Conn: capture.DB()}
+
+	v := &trackedT{}
+	if err := orm.Select(ctx, v, nil); err != nil {
+		t.Fatal(err)
+	}
+	if changed := zsql.Changed(v); len(changed) != 0 {
+		t.Fatal(changed)
+	}
+
+	v.FieldB = "c"
+	if changed := zsql.Changed(v); !reflect.DeepEqual(changed, []string{"field_b"}) {
+		t.Fatal(changed)
+	}
+
+	capture.Reset()
+	for i := 0; i < 2; i++ {
+		if _, err := orm.Update(ctx, v, nil, "WHERE `field_a` = ?", v.FieldA); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := capture.Render(), "UPDATE `test` SET `field_b` = 'c' WHERE `field_a` = 'a';\n"; got != want {
+		t.Fatalf("want %q got %q", want, got)
+	}
+}
@@ -0,0 +1,138 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+)
+
+type (
+	// handler of statements received by hook driver
+	hookHandler interface {
+		begin() error
+		commit() error
+		rollback() error
+		exec(statement string, args []interface{}) (driver.Result, error)
+		query(statement string, args []interface{}) (driver.Rows, error)
+	}
+
+	hookConnector struct{ handler hookHandler }
+
+	hookConn struct{ handler hookHandler }
+
+	hookStmt struct {
+		handler   hookHandler
+		statement string
+	}
+
+	hookTx struct{ handler hookHandler }
+
+	hookRows struct {
+		columns []string
+		types   []string
+		rows    [][]interface{}
+		offset  int
+	}
+)
+
+func openHookDB(handler hookHandler) *sql.DB { return sql.OpenDB(hookConnector{handler: handler}) }
+
+func (c hookConnector) Connect(context.Context) (driver.Conn, error) { return hookConn(c), nil }
+
+func (c hookConnector) Driver() driver.Driver { return c }
+
+func (c hookConnector) Open(string) (driver.Conn, error) { return hookConn(c), nil }
+
+func (c hookConn) Prepare(statement string) (driver.Stmt, error) {
+	return hookStmt{handler: c.handler, statement: statement}, nil
+}
+
+func (c hookConn) Close() error { return nil }
+
+func (c hookConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c hookConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return hookTx(c), c.handler.begin()
+}
+
+func (c hookConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if v, err := driver.DefaultParameterConverter.ConvertValue(nv.Value); err == nil {
+		nv.Value = v
+	}
+	return nil
+}
+
+func (c hookConn) ExecContext(_ context.Context, statement string, args []driver.NamedValue) (driver.Result, error) {
+	return c.handler.exec(statement, namedValues(args))
+}
+
+func (c hookConn) QueryContext(_ context.Context, statement string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.handler.query(statement, namedValues(args))
+}
+
+func (s hookStmt) Close() error { return nil }
+
+func (s hookStmt) NumInput() int { return -1 }
+
+func (s hookStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.handler.exec(s.statement, driverValues(args))
+}
+
+func (s hookStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.handler.query(s.statement, driverValues(args))
+}
+
+func (s hookStmt) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.handler.exec(s.statement, namedValues(args))
+}
+
+func (s hookStmt) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.handler.query(s.statement, namedValues(args))
+}
+
+func (tx hookTx) Commit() error { return tx.handler.commit() }
+
+func (tx hookTx) Rollback() error { return tx.handler.rollback() }
+
+func (r *hookRows) Columns() []string { return r.columns }
+
+func (r *hookRows) Close() error { return nil }
+
+func (r *hookRows) ColumnTypeDatabaseTypeName(i int) string {
+	if i < len(r.types) {
+		return r.types[i]
+	}
+	return ""
+}
+
+func (r *hookRows) Next(dst []driver.Value) error {
+	if r.offset >= len(r.rows) {
+		return io.EOF
+	}
+	for i, v := range r.rows[r.offset] {
+		if i < len(dst) {
+			dst[i] = v
+		}
+	}
+	r.offset++
+	return nil
+}
+
+func namedValues(args []driver.NamedValue) []interface{} {
+	values := make([]interface{}, len(args))
+	for i := range args {
+		values[i] = args[i].Value
+	}
+	return values
+}
+
+func driverValues(args []driver.Value) []interface{} {
+	values := make([]interface{}, len(args))
+	for i := range args {
+		values[i] = args[i]
+	}
+	return values
+}
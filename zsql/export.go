@@ -0,0 +1,162 @@
+package zsql
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+type (
+	exportWriter interface {
+		header(columns []string) error
+		row(columns []string, values []interface{}) error
+		flush() error
+	}
+
+	csvExporter struct{ w *csv.Writer }
+
+	jsonlExporter struct{ w *bufio.Writer }
+)
+
+// values are converted by column types before, so that remaining bytes are of binary columns
+func exportValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case []byte:
+		return base64.StdEncoding.EncodeToString(value)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+func (e csvExporter) header(columns []string) error { return e.w.Write(columns) }
+
+func (e csvExporter) row(_ []string, values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch value := exportValue(v).(type) {
+		case nil:
+		case string:
+			record[i] = value
+		case int64:
+			record[i] = strconv.FormatInt(value, 10)
+		case float64:
+			record[i] = strconv.FormatFloat(value, 'f', -1, 64)
+		case bool:
+			record[i] = strconv.FormatBool(value)
+		default:
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			record[i] = string(data)
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e csvExporter) flush() error { e.w.Flush(); return e.w.Error() }
+
+func (e jsonlExporter) header([]string) error { return nil }
+
+func (e jsonlExporter) row(columns []string, values []interface{}) error {
+	e.w.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(columns[i])
+		value, err := json.Marshal(exportValue(v))
+		if err != nil {
+			return err
+		}
+		e.w.Write(key)
+		e.w.WriteByte(':')
+		e.w.Write(value)
+	}
+	e.w.WriteString("}\n")
+	return nil
+}
+
+func (e jsonlExporter) flush() error { return e.w.Flush() }
+
+func export(rows *sql.Rows, dialect Dialect, columns []string, exporter exportWriter) (err error) {
+	defer rows.Close()
+	if len(columns) == 0 {
+		if columns, err = rows.Columns(); err != nil {
+			return
+		}
+	}
+	if err = exporter.header(columns); err != nil {
+		return
+	}
+
+	types := make([]string, len(columns))
+	if columnTypes, e := rows.ColumnTypes(); e == nil && len(columnTypes) == len(types) {
+		for i, typ := range columnTypes {
+			types[i] = typ.DatabaseTypeName()
+		}
+	}
+
+	values := make([]interface{}, len(columns))
+	dst := make([]interface{}, len(columns))
+	for i := range dst {
+		dst[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err = rows.Scan(dst...); err != nil {
+			return
+		}
+		for i, v := range values {
+			values[i] = dialect.ConvertValue(types[i], v)
+		}
+		if err = exporter.row(columns, values); err != nil {
+			return
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+	return exporter.flush()
+}
+
+func ExportCSV(ctx context.Context, conn Conn, w io.Writer, statement string, args ...interface{}) (err error) {
+	rows, err := conn.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return
+	}
+	return export(rows, DetectDialect(conn), nil, csvExporter{w: csv.NewWriter(w)})
+}
+
+func ExportJSONL(ctx context.Context, conn Conn, w io.Writer, statement string, args ...interface{}) (err error) {
+	rows, err := conn.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return
+	}
+	return export(rows, DetectDialect(conn), nil, jsonlExporter{w: bufio.NewWriter(w)})
+}
+
+func (orm Litorm) exportModel(ctx context.Context, exporter exportWriter, model Model, fields []string, ext []interface{}) (err error) {
+	FieldMapping{}.MapFields(model, &fields)
+	statement := orm.builder()
+	rows, err := orm.query(ctx, statement, statement.BuildSelect(model, fields, ext))
+	if err != nil {
+		return
+	}
+	return export(rows, orm.Dialect, fields, exporter)
+}
+
+func (orm Litorm) ExportCSV(ctx context.Context, w io.Writer, model Model, fields []string, ext ...interface{}) error {
+	return orm.exportModel(ctx, csvExporter{w: csv.NewWriter(w)}, model, fields, ext)
+}
+
+func (orm Litorm) ExportJSONL(ctx context.Context, w io.Writer, model Model, fields []string, ext ...interface{}) error {
+	return orm.exportModel(ctx, jsonlExporter{w: bufio.NewWriter(w)}, model, fields, ext)
+}
@@ -0,0 +1,53 @@
+package zsql_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestExport(t *testing.T) {
+	db := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{
+			Columns: []string{"field_b", "field_a"},
+			Rows:    [][]interface{}{{[]byte("a,b"), nil}, {int64(1), 1.5}},
+		}
+	}).DB()
+
+	buf := new(bytes.Buffer)
+	if err := zsql.ExportCSV(ctx, db, buf, "SELECT 1"); err != nil || buf.String() != "field_b,field_a\n\"a,b\",\n1,1.5\n" {
+		t.Fatal(err, buf.String())
+	}
+
+	buf.Reset()
+	if err := zsql.ExportJSONL(ctx, db, buf, "SELECT 1"); err != nil || buf.String() != "{\"field_b\":\"a,b\",\"field_a\":null}\n{\"field_b\":1,\"field_a\":1.5}\n" {
+		t.Fatal(err, buf.String())
+	}
+
+	buf.Reset()
+	if err := (zsql.Litorm{Conn: db}).ExportCSV(ctx, buf, &T{}, []string{"field_b", "field_a"}); err != nil || buf.String() != "field_b,field_a\n\"a,b\",\n1,1.5\n" {
+		t.Fatal(err, buf.String())
+	}
+}
+
+func TestExportBinary(t *testing.T) {
+	db := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{
+			Columns:     []string{"blob", "text", "n"},
+			ColumnTypes: []string{"BLOB", "VARCHAR", "BIGINT"},
+			Rows:        [][]interface{}{{[]byte{0x00, 0x01}, []byte("x"), []byte("12")}, {[]byte{0xff}, []byte("a"), []byte("3")}},
+		}
+	}).DB()
+
+	buf := new(bytes.Buffer)
+	if err := zsql.ExportJSONL(ctx, db, buf, "SELECT 1"); err != nil ||
+		buf.String() != "{\"blob\":\"AAE=\",\"text\":\"x\",\"n\":12}\n{\"blob\":\"/w==\",\"text\":\"a\",\"n\":3}\n" {
+		t.Fatal(err, buf.String())
+	}
+
+	buf.Reset()
+	if err := zsql.ExportCSV(ctx, db, buf, "SELECT 1"); err != nil || buf.String() != "blob,text,n\nAAE=,x,12\n/w==,a,3\n" {
+		t.Fatal(err, buf.String())
+	}
+}
@@ -0,0 +1,68 @@
+package zsql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnsafeIdentifier = errors.New("unsafe identifier")
+
+// Expr is trusted expression written verbatim in strict mode.
+// Being a distinct type, untrusted string input can not be passed as Expr without explicit conversion.
+type Expr string
+
+func IsIdentifier(v string) bool {
+	for _, part := range strings.Split(v, ".") {
+		if len(part) == 0 || !isIdentRune(part[0], true) {
+			return false
+		}
+		for i := 1; i < len(part); i++ {
+			if !isWordByte(part[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (bd *SqlBuilder) Err() error { return bd.err }
+
+func (bd *SqlBuilder) fail(err error) {
+	if bd.err == nil {
+		bd.err = err
+	}
+}
+
+func (bd *SqlBuilder) writeIdentifier(v string) {
+	if !IsIdentifier(v) {
+		bd.fail(fmt.Errorf("%w: %q", ErrUnsafeIdentifier, v))
+		return
+	}
+	for i, part := range strings.Split(v, ".") {
+		if i > 0 {
+			bd.WriteRune('.')
+		}
+		bd.quote(part)
+	}
+}
+
+func (bd *SqlBuilder) writeStrictField(field string) {
+	if _, ok := bd.allow[field]; bd.allow != nil && !ok {
+		bd.fail(fmt.Errorf("%w: %q not in field mapping", ErrUnsafeIdentifier, field))
+	} else {
+		bd.writeIdentifier(field)
+	}
+}
+
+// SanitizeFields filters fields from untrusted input by model field mapping.
+func SanitizeFields(model Model, fields []string) ([]string, error) {
+	mapping := make(FieldMapping, len(fields))
+	model.FieldMapping(mapping)
+	for _, field := range fields {
+		if _, ok := mapping[field]; !ok {
+			return nil, fmt.Errorf("%w: %q not in field mapping", ErrUnsafeIdentifier, field)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,129 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrNoPrimaryKey = errors.New("model has no primary key")
+
+type PrimaryKey interface {
+	PrimaryKey() []string
+}
+
+func primaryKeys(model Model) (keys []string, err error) {
+	if pk, ok := model.(PrimaryKey); ok {
+		keys = pk.PrimaryKey()
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoPrimaryKey, model.TableName())
+	}
+	return
+}
+
+func (orm Litorm) keyCondition(model Model) (condition string, args []interface{}, err error) {
+	keys, err := primaryKeys(model)
+	if err != nil {
+		return
+	}
+	return orm.keysCondition(model, keys)
+}
+
+func (orm Litorm) keysCondition(model Model, keys []string) (condition string, args []interface{}, err error) {
+	mapping := make(FieldMapping, len(keys))
+	model.FieldMapping(mapping)
+	for _, key := range keys {
+		if _, ok := mapping[key]; !ok {
+			return "", nil, fmt.Errorf("%w: %s not in field mapping", ErrNoPrimaryKey, key)
+		}
+	}
+
+	bd := orm.builder()
+	bd.allow = mapping
+	bd.WriteString("WHERE ")
+	bd.WriteFields(keys, true, " = ?", " AND ")
+	mapping.MapValues(keys, &args)
+	return bd.String(), args, bd.err
+}
+
+// Get selects model by values of its primary key fields.
+func (orm Litorm) Get(ctx context.Context, model Model, fields []string) (err error) {
+	condition, args, err := orm.keyCondition(model)
+	if err != nil {
+		return
+	}
+	return orm.Select(ctx, model, fields, append([]interface{}{condition}, args...)...)
+}
+
+func (orm Litorm) DeleteByKey(ctx context.Context, model Model) (result sql.Result, err error) {
+	condition, args, err := orm.keyCondition(model)
+	if err != nil {
+		return
+	}
+	return orm.Delete(ctx, model, condition, args...)
+}
+
+// Save inserts model or updates non-key fields when primary key conflicts.
+func (orm Litorm) Save(ctx context.Context, model Model, fields []string) (result sql.Result, err error) {
+	keys, err := primaryKeys(model)
+	if err != nil {
+		return
+	}
+
+	FieldMapping{}.MapFields(model, &fields)
+	statement := orm.builder()
+	args, err := statement.BuildInsert(modelItem{Model: model}, false, fields, nil)
+	if err != nil {
+		return
+	}
+
+	updates := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !containsString(keys, field) {
+			updates = append(updates, field)
+		}
+	}
+	statement.WriteUpsert(keys, updates)
+
+	if orm.ReturnID && orm.Dialect != MySQL {
+		return orm.insertsID(ctx, statement, modelItem{Model: model}, args)
+	}
+	return orm.exec(ctx, statement, args)
+}
+
+// WriteUpsert writes dialect conflict clause updating fields with inserted values.
+func (bd *SqlBuilder) WriteUpsert(keys, fields []string) {
+	if bd.Dialect == MySQL {
+		bd.WriteString(" ON DUPLICATE KEY UPDATE ")
+		if len(fields) == 0 {
+			bd.WriteFields(keys[:1], true, " = ", "")
+			bd.WriteFields(keys[:1], true, "", "")
+			return
+		}
+		for i, field := range fields {
+			if i > 0 {
+				bd.WriteRune(',')
+			}
+			bd.WriteFields([]string{field}, true, " = VALUES(", "")
+			bd.WriteFields([]string{field}, true, ")", "")
+		}
+		return
+	}
+
+	bd.WriteString(" ON CONFLICT (")
+	bd.WriteFields(keys, true, "", ",")
+	if len(fields) == 0 {
+		bd.WriteString(") DO NOTHING")
+		return
+	}
+	bd.WriteString(") DO UPDATE SET ")
+	for i, field := range fields {
+		if i > 0 {
+			bd.WriteRune(',')
+		}
+		bd.WriteFields([]string{field}, true, " = EXCLUDED.", "")
+		bd.WriteFields([]string{field}, true, "", "")
+	}
+}
@@ -0,0 +1,160 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	ErrLockTimeout     = errors.New("lock timeout")
+	ErrLockUnsupported = errors.New("lock unsupported")
+
+	LockPollInterval = 50 * time.Millisecond
+)
+
+// DetectDialect detects dialect by import path of database driver, MySQL if unknown.
+func DetectDialect(db interface{}) Dialect {
+	d, ok := db.(interface{ Driver() driver.Driver })
+	if !ok {
+		return MySQL
+	} else if hook, ok := d.Driver().(hookConnector); ok {
+		if capture, ok := hook.handler.(*Capture); ok {
+			return capture.Dialect
+		}
+		return MySQL
+	}
+
+	typ := reflect.TypeOf(d.Driver())
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch path := strings.ToLower(typ.PkgPath()); {
+	case strings.Contains(path, "lib/pq"), strings.Contains(path, "jackc/pgx"), strings.Contains(path, "postgres"):
+		return PostgreSQL
+	case strings.Contains(path, "sqlite"):
+		return SQLite
+	}
+	return MySQL
+}
+
+func WithLock(ctx context.Context, db DB, name string, timeout time.Duration, fn func(context.Context) error) error {
+	return DetectDialect(db).WithLock(ctx, db, name, timeout, fn)
+}
+
+func WithTxLock(ctx context.Context, db DB, name string, timeout time.Duration, fn func(context.Context) error) error {
+	return DetectDialect(db).WithTxLock(ctx, db, name, timeout, fn)
+}
+
+func (d Dialect) WithLock(ctx context.Context, db DB, name string, timeout time.Duration, fn func(context.Context) error) (err error) {
+	pinner, ok := db.(interface {
+		Conn(ctx context.Context) (*sql.Conn, error)
+	})
+	if !ok {
+		return ErrLockUnsupported
+	}
+
+	conn, err := pinner.Conn(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if err = d.lock(ctx, conn, name, timeout, false); err != nil {
+		return
+	}
+
+	defer func() {
+		if uerr := d.unlock(context.Background(), conn, name); uerr != nil {
+			// discard connection to drop session lock with it
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+			if err == nil {
+				err = uerr
+			}
+		}
+	}()
+	return fn(ctx)
+}
+
+// WithTxLock runs fn in session transaction holding lock until transaction finished.
+// MySQL has no transaction level lock, the lock is taken on another pinned connection
+// and released after commit or rollback, so it is unsupported inside existing session.
+func (d Dialect) WithTxLock(ctx context.Context, db DB, name string, timeout time.Duration, fn func(context.Context) error) error {
+	if d == MySQL {
+		if InSession(ctx, db) {
+			return ErrLockUnsupported
+		}
+		return d.WithLock(ctx, db, name, timeout, func(ctx context.Context) error {
+			return WithSessionTx(ctx, db, fn)
+		})
+	}
+	return WithSessionTx(ctx, db, func(ctx context.Context) (err error) {
+		conn := ctx.Value(sessionKey{DB: db}).(*sessionTx).Conn
+		if err = d.lock(ctx, conn, name, timeout, true); err != nil {
+			return
+		}
+		return fn(ctx)
+	})
+}
+
+func (d Dialect) lock(ctx context.Context, conn Conn, name string, timeout time.Duration, tx bool) (err error) {
+	switch d {
+	case MySQL:
+		var ret sql.NullInt64
+		if err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, timeout.Seconds()).Scan(&ret); err != nil {
+			return
+		} else if !ret.Valid {
+			return fmt.Errorf("get lock %s failed", name)
+		} else if ret.Int64 == 0 {
+			return ErrLockTimeout
+		}
+		return
+	case PostgreSQL:
+		statement := "SELECT pg_try_advisory_lock(hashtext($1))"
+		if tx {
+			statement = "SELECT pg_try_advisory_xact_lock(hashtext($1))"
+		}
+
+		deadline := time.Now().Add(timeout)
+		ticker := time.NewTicker(LockPollInterval)
+		defer ticker.Stop()
+
+		for {
+			var locked bool
+			if err = conn.QueryRowContext(ctx, statement, name).Scan(&locked); err != nil || locked {
+				return
+			} else if timeout >= 0 && time.Now().After(deadline) {
+				return ErrLockTimeout
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	default:
+		return ErrLockUnsupported
+	}
+}
+
+func (d Dialect) unlock(ctx context.Context, conn Conn, name string) (err error) {
+	var ret sql.NullBool
+	switch d {
+	case MySQL:
+		err = conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", name).Scan(&ret)
+	case PostgreSQL:
+		err = conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", name).Scan(&ret)
+	default:
+		return ErrLockUnsupported
+	}
+	if err == nil && !ret.Bool {
+		err = fmt.Errorf("release lock %s failed", name)
+	}
+	return
+}
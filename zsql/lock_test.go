@@ -0,0 +1,83 @@
+package zsql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestWithLock(t *testing.T) {
+	locked := int64(1)
+	capture := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{Columns: []string{"ret"}, Rows: [][]interface{}{{locked}}}
+	})
+
+	err := zsql.WithLock(ctx, capture.DB(), "job", time.Second, func(ctx context.Context) error { return nil })
+	if want := "SELECT GET_LOCK('job', 1);\nSELECT RELEASE_LOCK('job');\n"; err != nil || capture.Render() != want {
+		t.Fatal(err, capture.Render())
+	}
+
+	locked = 0
+	err = zsql.WithLock(ctx, capture.DB(), "job", time.Second, func(ctx context.Context) error { return nil })
+	if !errors.Is(err, zsql.ErrLockTimeout) {
+		t.Fatal(err)
+	}
+}
+
+func TestWithTxLock(t *testing.T) {
+	capture := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{Columns: []string{"ret"}, Rows: [][]interface{}{{int64(1)}}}
+	})
+	db := capture.DB()
+
+	err := zsql.WithTxLock(ctx, db, "job", time.Second, func(ctx context.Context) error {
+		_, err := zsql.SessionConn(db).ExecContext(ctx, "DELETE FROM job")
+		return err
+	})
+	want := "SELECT GET_LOCK('job', 1);\nBEGIN;\nDELETE FROM job;\nCOMMIT;\nSELECT RELEASE_LOCK('job');\n"
+	if err != nil || capture.Render() != want {
+		t.Fatal(err, capture.Render())
+	}
+
+	err = zsql.WithSessionTx(ctx, db, func(ctx context.Context) error {
+		return zsql.WithTxLock(ctx, db, "job", time.Second, func(ctx context.Context) error { return nil })
+	})
+	if !errors.Is(err, zsql.ErrLockUnsupported) {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresLock(t *testing.T) {
+	locked := true
+	capture := zsql.NewCapture(zsql.PostgreSQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{Columns: []string{"ret"}, Rows: [][]interface{}{{locked}}}
+	})
+	db := capture.DB()
+
+	if d := zsql.DetectDialect(db); d != zsql.PostgreSQL {
+		t.Fatal(d)
+	}
+
+	err := zsql.WithLock(ctx, db, "job", time.Second, func(ctx context.Context) error { return nil })
+	want := "SELECT pg_try_advisory_lock(hashtext('job'));\nSELECT pg_advisory_unlock(hashtext('job'));\n"
+	if err != nil || capture.Render() != want {
+		t.Fatal(err, capture.Render())
+	}
+
+	capture.Reset()
+	err = zsql.WithTxLock(ctx, db, "job", time.Second, func(ctx context.Context) error { return nil })
+	want = "BEGIN;\nSELECT pg_try_advisory_xact_lock(hashtext('job'));\nCOMMIT;\n"
+	if err != nil || capture.Render() != want {
+		t.Fatal(err, capture.Render())
+	}
+
+	locked = false
+	capture.Reset()
+	err = zsql.WithLock(ctx, db, "job", zsql.LockPollInterval, func(ctx context.Context) error { return nil })
+	if !errors.Is(err, zsql.ErrLockTimeout) || len(capture.Captured()) < 2 {
+		t.Fatal(err, capture.Render())
+	}
+}
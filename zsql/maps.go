@@ -0,0 +1,125 @@
+package zsql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dialectTimeLayouts = map[Dialect][]string{
+	MySQL: {
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02",
+		"15:04:05.999999999",
+	},
+	PostgreSQL: {
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999Z07",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02",
+	},
+	SQLite: {
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02T15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04",
+		"2006-01-02T15:04",
+		"2006-01-02",
+	},
+}
+
+func (d Dialect) ParseTime(v string) (t time.Time, ok bool) {
+	for _, layout := range dialectTimeLayouts[d] {
+		if t, err := time.ParseInLocation(layout, v, time.UTC); err == nil {
+			return t, true
+		}
+	}
+	return
+}
+
+// ConvertValue converts driver value of column with database type name into sensible go value.
+func (d Dialect) ConvertValue(databaseType string, v interface{}) interface{} {
+	var str string
+	switch value := v.(type) {
+	case []byte:
+		str = string(value)
+	case string:
+		str = value
+	default:
+		return v
+	}
+
+	switch typ := strings.ToUpper(databaseType); {
+	case strings.Contains(typ, "BLOB"), strings.Contains(typ, "BINARY"), typ == "BYTEA", typ == "BIT":
+		if b, ok := v.([]byte); ok {
+			return append([]byte(nil), b...)
+		}
+	case strings.Contains(typ, "INT"):
+		if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return n
+		}
+	case typ == "FLOAT", typ == "DOUBLE", typ == "REAL", typ == "FLOAT4", typ == "FLOAT8":
+		if n, err := strconv.ParseFloat(str, 64); err == nil {
+			return n
+		}
+	case strings.Contains(typ, "DATE"), strings.Contains(typ, "TIME"):
+		if t, ok := d.ParseTime(str); ok {
+			return t
+		}
+	}
+	return str
+}
+
+func (orm Litorm) QueryMapsFunc(ctx context.Context, fn func(columns []string, row map[string]interface{}) error, statement string, args ...interface{}) (err error) {
+	if statement, args, err = orm.Dialect.Rebind(statement, args); err != nil {
+		return
+	}
+
+	rows, err := orm.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return
+	}
+
+	types := make([]string, len(columns))
+	if columnTypes, e := rows.ColumnTypes(); e == nil {
+		for i, typ := range columnTypes {
+			types[i] = typ.DatabaseTypeName()
+		}
+	}
+
+	values := make([]interface{}, len(columns))
+	dst := make([]interface{}, len(columns))
+	for i := range dst {
+		dst[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err = rows.Scan(dst...); err != nil {
+			return
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = orm.Dialect.ConvertValue(types[i], values[i])
+		}
+		if err = fn(columns, row); err != nil {
+			return
+		}
+	}
+	return rows.Err()
+}
+
+func (orm Litorm) QueryMaps(ctx context.Context, statement string, args ...interface{}) (list []map[string]interface{}, err error) {
+	err = orm.QueryMapsFunc(ctx, func(_ []string, row map[string]interface{}) error {
+		list = append(list, row)
+		return nil
+	}, statement, args...)
+	return
+}
@@ -0,0 +1,35 @@
+package zsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestQueryMaps(t *testing.T) {
+	db := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{
+			Columns:     []string{"id", "name", "created", "data"},
+			ColumnTypes: []string{"BIGINT", "VARCHAR", "DATETIME", "BLOB"},
+			Rows:        [][]interface{}{{[]byte("1"), []byte("a"), []byte("2023-01-02 03:04:05"), []byte{0}}},
+		}
+	}).DB()
+
+	var columns []string
+	rows, err := zsql.Litorm{Conn: db}.QueryMaps(ctx, "SELECT * FROM `test` WHERE `id` = :id", zsql.NamedArgs{"id": 1})
+	if err != nil || len(rows) != 1 {
+		t.Fatal(rows, err)
+	}
+	if row := rows[0]; row["id"] != int64(1) || row["name"] != "a" ||
+		!row["created"].(time.Time).Equal(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)) || len(row["data"].([]byte)) != 1 {
+		t.Fatal(row)
+	}
+
+	if err = (zsql.Litorm{Conn: db}).QueryMapsFunc(ctx, func(cols []string, row map[string]interface{}) error {
+		columns = cols
+		return nil
+	}, "SELECT 1"); err != nil || len(columns) != 4 || columns[3] != "data" {
+		t.Fatal(columns, err)
+	}
+}
@@ -0,0 +1,97 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type PartialCommitError struct {
+	Committed       []DB
+	Failed          DB
+	Err             error
+	CompensationErr error
+}
+
+func (e *PartialCommitError) Error() string {
+	msg := fmt.Sprintf("partial commit after %d committed sessions: %v", len(e.Committed), e.Err)
+	if e.CompensationErr != nil {
+		msg += fmt.Sprintf(". compensation error: %v", e.CompensationErr)
+	}
+	return msg
+}
+
+func (e *PartialCommitError) Unwrap() error { return e.Err }
+
+func WithMultiSessionTx(ctx context.Context, dbs []DB, fn func(context.Context) error) (err error) {
+	opt := loadTxOption(ctx)
+	ctx, cancel := opt.timeout(ctx)
+	defer cancel()
+	sctx := ctx
+
+	owned := make([]DB, 0, len(dbs))
+	txs := make([]*sql.Tx, 0, len(dbs))
+	stxs := make([]*sessionTx, 0, len(dbs))
+
+	for _, db := range dbs {
+		key := sessionKey{DB: db}
+		if _, in := sctx.Value(key).(*sessionTx); in {
+			continue
+		}
+		tx, e := db.BeginTx(ctx, opt.SqlTxOptions)
+		if err = e; err != nil {
+			break
+		}
+		stx := &sessionTx{Conn: tx}
+		defer stx.track(opt)()
+		owned, txs, stxs = append(owned, db), append(txs, tx), append(stxs, stx)
+		sctx = context.WithValue(sctx, key, stx)
+	}
+
+	if err == nil {
+		err = func() (err error) {
+			defer func() {
+				if e := recover(); e != nil {
+					err = opt.recover(e)
+				}
+			}()
+			return fn(sctx)
+		}()
+	}
+
+	committed, rollback := 0, 0
+	if err == nil {
+		for ; committed < len(txs); committed++ {
+			if err = txs[committed].Commit(); err != nil {
+				rollback = committed + 1
+				break
+			}
+		}
+	}
+
+	if cause := err; cause != nil {
+		for i := rollback; i < len(txs); i++ {
+			if rerr := opt.rollback(txs[i], cause); rerr != nil && rerr != cause {
+				err = rerr
+			}
+		}
+	}
+
+	if committed > 0 && committed < len(txs) {
+		perr := &PartialCommitError{Committed: owned[:committed], Failed: owned[committed], Err: err}
+		if opt.Compensation != nil {
+			perr.CompensationErr = callback(func() error { return opt.Compensation(ctx, perr) })
+		}
+		err = perr
+	}
+
+	var errs []error
+	for i, stx := range stxs {
+		if i < committed {
+			errs = append(errs, stx.callbacks(ctx, opt, nil)...)
+		} else {
+			errs = append(errs, stx.callbacks(ctx, opt, err)...)
+		}
+	}
+	return opt.callbackError(err, errs)
+}
@@ -0,0 +1,50 @@
+package zsql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestMultiSessionPartialCommit(t *testing.T) {
+	commitErr := errors.New("commit failed")
+	db1 := zsql.NewCapture(zsql.MySQL, nil).DB()
+	db2 := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		if statement == "COMMIT" {
+			return zsql.CaptureResult{Err: commitErr}
+		}
+		return zsql.CaptureResult{}
+	}).DB()
+
+	compensated := false
+	ctx := zsql.WithTxOptions(ctx, zsql.WithCompensation(func(ctx context.Context, err *zsql.PartialCommitError) error {
+		compensated = len(err.Committed) == 1 && err.Committed[0] == db1 && err.Failed == db2
+		return nil
+	}))
+
+	err := zsql.WithMultiSessionTx(ctx, []zsql.DB{db1, db2}, func(ctx context.Context) error { return nil })
+
+	var perr *zsql.PartialCommitError
+	if !errors.As(err, &perr) || !errors.Is(err, commitErr) || !compensated {
+		t.Fatal(err, compensated)
+	}
+}
+
+func TestMultiSessionRollback(t *testing.T) {
+	captures := []*zsql.Capture{zsql.NewCapture(zsql.MySQL, nil), zsql.NewCapture(zsql.MySQL, nil)}
+	dbs := []zsql.DB{captures[0].DB(), captures[1].DB()}
+	cause := errors.New("boom")
+
+	ctx := zsql.WithTxOptions(ctx, zsql.WithRollback(func(rollback func() error, _ error) error { return rollback() }))
+	err := zsql.WithMultiSessionTx(ctx, dbs, func(ctx context.Context) error { return cause })
+	if !errors.Is(err, cause) {
+		t.Fatal(err)
+	}
+	for _, capture := range captures {
+		if got := capture.Render(); got != "BEGIN;\nROLLBACK;\n" {
+			t.Fatal(got)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package zsql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-zing/gozz-kit/zreflect"
+)
+
+type NamedArgs map[string]interface{}
+
+func Bind(v interface{}) (args NamedArgs) {
+	args = make(NamedArgs)
+	switch value := v.(type) {
+	case nil:
+	case NamedArgs:
+		return value
+	case map[string]interface{}:
+		return value
+	case Model:
+		value.FieldMapping(args)
+		if atomic.LoadInt32(&convertersSize) > 0 {
+			for name, arg := range args {
+				args[name] = convertPtr(arg)
+			}
+		}
+	default:
+		rv := reflect.ValueOf(v)
+		for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if rv.Kind() == reflect.Struct {
+			bindStruct(rv, args)
+		}
+	}
+	return
+}
+
+func bindStruct(rv reflect.Value, args NamedArgs) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := string(zreflect.ParseTag(string(field.Tag)).Get("db").Split(",")[0])
+		fv := rv.Field(i)
+		if name == "-" || len(field.PkgPath) > 0 && !field.Anonymous {
+			continue
+		} else if len(name) == 0 && field.Anonymous {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			// fields of embedded struct are promoted, other unexported embeds are not accessible
+			if fv.Kind() == reflect.Struct {
+				bindStruct(fv, args)
+				continue
+			} else if len(field.PkgPath) > 0 {
+				continue
+			}
+			fv = rv.Field(i)
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		if _, exist := args[name]; !exist {
+			args[name] = convertArg(fv.Interface())
+		}
+	}
+}
+
+func splitNamedArgs(args []interface{}) (positional []interface{}, named NamedArgs) {
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case NamedArgs:
+			if named == nil {
+				named = make(NamedArgs, len(v))
+			}
+			for k, value := range v {
+				named[k] = convertArg(value)
+			}
+		case sql.NamedArg:
+			if named == nil {
+				named = make(NamedArgs)
+			}
+			named[v.Name] = convertArg(v.Value)
+		default:
+			positional = append(positional, convertArg(arg))
+		}
+	}
+	return
+}
+
+func isIdentRune(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
+
+// walk statement outside quoted strings, quoted identifiers and comments.
+// fn is called with each placeholder found, name is empty for positional one,
+// or starts with ':' '@' for named one and '$' for numbered one.
+func (d Dialect) walkPlaceholders(statement string, fn func(start, end int, name string) error) error {
+	for i := 0; i < len(statement); i++ {
+		switch c := statement[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(statement) && statement[i] != c; i++ {
+				if statement[i] == '\\' && c == '\'' && d == MySQL {
+					i++
+				}
+			}
+		case c == '-' && strings.HasPrefix(statement[i:], "--"), c == '#' && d == MySQL:
+			if n := strings.IndexByte(statement[i:], '\n'); n >= 0 {
+				i += n
+			} else {
+				i = len(statement)
+			}
+		case c == '/' && strings.HasPrefix(statement[i:], "/*"):
+			if n := strings.Index(statement[i+2:], "*/"); n >= 0 {
+				i += n + 3
+			} else {
+				i = len(statement)
+			}
+		case c == '?':
+			if err := fn(i, i+1, ""); err != nil {
+				return err
+			}
+		case c == '$' && d == PostgreSQL:
+			end := i + 1
+			for end < len(statement) && statement[end] >= '0' && statement[end] <= '9' {
+				end++
+			}
+			if end > i+1 {
+				if err := fn(i, end, statement[i:end]); err != nil {
+					return err
+				}
+				i = end - 1
+			}
+		case c == ':' || c == '@':
+			if i+1 >= len(statement) || !isIdentRune(statement[i+1], true) {
+				for i+1 < len(statement) && statement[i+1] == c {
+					i++
+				}
+				continue
+			}
+			end := i + 1
+			for end < len(statement) && isIdentRune(statement[end], false) {
+				end++
+			}
+			if err := fn(i, end, statement[i:end]); err != nil {
+				return err
+			}
+			i = end - 1
+		}
+	}
+	return nil
+}
+
+// Rebind binds positional, numbered and named placeholders to dialect placeholders in order.
+// Numbered placeholder $n binds the n-th positional arg.
+func (d Dialect) Rebind(statement string, args []interface{}) (string, []interface{}, error) {
+	positional, named := splitNamedArgs(args)
+	if named == nil && d.Placeholder(1) == "?" {
+		return statement, positional, nil
+	}
+
+	bd := new(strings.Builder)
+	bound := make([]interface{}, 0, len(args))
+	numbered := make([]bool, len(positional))
+	last, index := 0, 0
+
+	err := d.walkPlaceholders(statement, func(start, end int, name string) error {
+		var value interface{}
+		if len(name) > 0 && name[0] == '$' {
+			n, _ := strconv.Atoi(name[1:])
+			if n < 1 || n > len(positional) {
+				return fmt.Errorf("missing positional argument %d", n)
+			}
+			value, numbered[n-1] = positional[n-1], true
+		} else if len(name) > 0 && named == nil {
+			return nil
+		} else if len(name) == 0 {
+			if index >= len(positional) {
+				return fmt.Errorf("missing positional argument %d", index+1)
+			}
+			value, index = positional[index], index+1
+		} else if v, ok := named[name[1:]]; ok {
+			value = v
+		} else if name[0] == '@' {
+			return nil
+		} else {
+			return fmt.Errorf("missing named argument %s", name)
+		}
+		bound = append(bound, value)
+		bd.WriteString(statement[last:start])
+		bd.WriteString(d.Placeholder(len(bound)))
+		last = end
+		return nil
+	})
+
+	unused := 0
+	for i := index; i < len(positional); i++ {
+		if !numbered[i] {
+			unused++
+		}
+	}
+
+	if err != nil {
+		return "", nil, err
+	} else if unused > 0 {
+		return "", nil, fmt.Errorf("unused positional arguments %d", unused)
+	}
+	bd.WriteString(statement[last:])
+	return bd.String(), bound, nil
+}
+
+func (bd *SqlBuilder) Rebind(args []interface{}) (string, []interface{}, error) {
+	if bd.err != nil {
+		return "", nil, bd.err
+	}
+	return bd.Dialect.Rebind(bd.String(), args)
+}
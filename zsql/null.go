@@ -0,0 +1,68 @@
+package zsql
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+type (
+	NullDefaulter interface {
+		NullDefault(field string) (value interface{})
+	}
+
+	NullRecorder interface {
+		RecordNulls(fields []string)
+	}
+
+	nullScan struct {
+		field  string
+		dst    reflect.Value
+		holder reflect.Value
+	}
+
+	nullScans []nullScan
+)
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+func wrapNulls(fields []string, values []interface{}) (scans nullScans) {
+	for i, v := range values {
+		if _, ok := v.(sql.Scanner); ok || v == nil {
+			continue
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			continue
+		}
+		switch elem := rv.Elem(); {
+		case elem.Kind() == reflect.Ptr, elem.Kind() == reflect.Interface, elem.Type() == bytesType:
+			continue
+		}
+		holder := reflect.New(rv.Type())
+		values[i] = holder.Interface()
+		scans = append(scans, nullScan{field: fields[i], dst: rv.Elem(), holder: holder})
+	}
+	return
+}
+
+func (scans nullScans) assign(model Model) {
+	var nulls []string
+	defaulter, _ := model.(NullDefaulter)
+	for _, scan := range scans {
+		if ptr := scan.holder.Elem(); !ptr.IsNil() {
+			scan.dst.Set(ptr.Elem())
+			continue
+		}
+		nulls = append(nulls, scan.field)
+		value := reflect.Zero(scan.dst.Type())
+		if defaulter != nil {
+			if v := reflect.ValueOf(defaulter.NullDefault(scan.field)); v.IsValid() && v.Type().ConvertibleTo(value.Type()) {
+				value = v.Convert(value.Type())
+			}
+		}
+		scan.dst.Set(value)
+	}
+	if recorder, ok := model.(NullRecorder); ok {
+		recorder.RecordNulls(nulls)
+	}
+}
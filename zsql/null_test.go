@@ -0,0 +1,39 @@
+package zsql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+type nullT struct {
+	T
+	nulls []string
+}
+
+func (t *nullT) NullDefault(field string) interface{} {
+	if field == "field_b" {
+		return "default"
+	}
+	return nil
+}
+
+func (t *nullT) RecordNulls(fields []string) { t.nulls = fields }
+
+func TestNullSafe(t *testing.T) {
+	db := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{Columns: []string{"field_a", "field_b"}, Rows: [][]interface{}{{nil, nil}}}
+	}).DB()
+
+	v := &nullT{T: T{FieldA: "a", FieldB: "b"}}
+	if err := (zsql.Litorm{Conn: db}).Select(ctx, v, nil); err == nil || !strings.Contains(err.Error(), "NULL") {
+		t.Fatal(err)
+	}
+	if err := (zsql.Litorm{Conn: db, NullSafe: true}).Select(ctx, v, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v.FieldA != "" || v.FieldB != "default" || len(v.nulls) != 2 {
+		t.Fatal(v)
+	}
+}
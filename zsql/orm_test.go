@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/go-zing/gozz-kit/zsql"
@@ -158,3 +159,288 @@ func TestInsert(t *testing.T) {
 		check(t, err)
 	}
 }
+
+func TestPreload(t *testing.T) {
+	st := &sliceT{{FieldA: "a"}, {FieldA: "b"}, {FieldA: "a"}}
+	check(t, newAssert("SELECT `field_a`,`field_b` FROM `test` WHERE `field_b` IN (?,?)", "a", "b").
+		Preload(ctx, st, zsql.Relation{
+			New:        func() zsql.Model { return &T{} },
+			ForeignKey: "field_b",
+			ParentKey:  "field_a",
+			Attach:     func(parent, child zsql.Model) {},
+		}))
+}
+
+func TestNamed(t *testing.T) {
+	check(t, newAssert("SELECT `field_a` FROM `test` WHERE `field_b` = ? AND `field_a` IN (?,?) AND x = ':b'", 1, 2, 1).
+		Select(ctx, &T{}, []string{"field_a"}, "WHERE `field_b` = :b AND `field_a` IN (?,:b) AND x = ':b'", 2, zsql.NamedArgs{"b": 1}))
+
+	v := &T{FieldB: "b"}
+	orm := zsql.Litorm{Conn: assertSql{
+		Statement: `UPDATE "test" SET "field_a" = $1 WHERE "field_b" = $2 AND x::text = $3 -- :c`,
+		Args:      []interface{}{&v.FieldA, &v.FieldB, 3},
+	}, Dialect: zsql.PostgreSQL}
+	_, err := orm.Update(ctx, v, []string{"field_a"}, `WHERE "field_b" = :field_b AND x::text = @c -- :c`, zsql.Bind(v), sql.Named("c", 3))
+	check(t, err)
+
+	check(t, zsql.Litorm{Conn: assertSql{
+		Statement: `SELECT "field_a" FROM "test" WHERE "field_a" = $1 OR "field_b" = $2 OR "field_a" = $3`,
+		Args:      []interface{}{5, 6, 5},
+	}, Dialect: zsql.PostgreSQL}.Select(ctx, &T{}, []string{"field_a"}, `WHERE "field_a" = $1 OR "field_b" = $2 OR "field_a" = $1`, 5, 6))
+}
+
+type (
+	bindInner struct{ A int }
+
+	bindOuter struct {
+		*bindInner
+		B int `db:"b"`
+	}
+
+	bindFlag int
+
+	bindHidden struct {
+		bindFlag
+		C int
+	}
+)
+
+func TestBind(t *testing.T) {
+	if args := zsql.Bind(&bindOuter{bindInner: &bindInner{A: 1}, B: 2}); args["A"] != 1 || args["b"] != 2 {
+		t.Fatal(args)
+	}
+	if args := zsql.Bind(bindOuter{B: 2}); len(args) != 1 || args["b"] != 2 {
+		t.Fatal(args)
+	}
+	if args := zsql.Bind(bindHidden{bindFlag: 1, C: 3}); len(args) != 1 || args["C"] != 3 {
+		t.Fatal(args)
+	}
+}
+
+type autoT struct {
+	T
+	ID int64
+}
+
+func (t *autoT) AutoIDField() string { return "id" }
+
+func (t *autoT) SetAutoID(id int64) { t.ID = id }
+
+func TestInsertReturnID(t *testing.T) {
+	v := &autoT{}
+	orm := zsql.Litorm{Conn: assertSql{
+		Statement: `INSERT INTO "test" ("field_a") VALUES ($1) ON CONFLICT DO NOTHING RETURNING "id"`,
+		Args:      []interface{}{&v.FieldA},
+	}, Dialect: zsql.PostgreSQL, ReturnID: true}
+	_, err := orm.Insert(ctx, true, v, []string{"field_a"})
+	check(t, err)
+}
+
+func TestDelete(t *testing.T) {
+	_, err := newAssert("DELETE FROM `test` WHERE `field_a` = ?", 1).
+		Delete(ctx, &T{}, "WHERE `field_a` = ?", 1)
+	check(t, err)
+}
+
+func TestTenant(t *testing.T) {
+	orm := zsql.NewTenantOrm(newAssert(
+		"SELECT `field_a` FROM `test` WHERE `field_b` = ? AND (`field_a` = ? OR `field_a` IN (SELECT 1 LIMIT 1)) ORDER BY `field_a` LIMIT 1",
+		"t1", 1), "field_b")
+
+	if err := orm.Select(ctx, &T{}, []string{"field_a"}, "WHERE `field_a` = ? OR `field_a` IN (SELECT 1 LIMIT 1) ORDER BY `field_a` LIMIT 1", 1); !errors.Is(err, zsql.ErrNoTenant) {
+		t.Fatal(err)
+	}
+
+	tctx := zsql.WithTenant(ctx, "t1")
+	check(t, orm.Select(tctx, &T{}, []string{"field_a"}, "WHERE `field_a` = ? OR `field_a` IN (SELECT 1 LIMIT 1) ORDER BY `field_a` LIMIT 1", 1))
+
+	v := &T{}
+	orm = zsql.NewTenantOrm(newAssert("INSERT INTO `test` (`field_a`,`field_b`) VALUES (?,?)", &v.FieldA, &v.FieldB), "field_b")
+	_, err := orm.Insert(tctx, false, v, []string{"field_a"})
+	if check(t, err); v.FieldB != "t1" {
+		t.Fatal(v)
+	}
+
+	orm = zsql.NewTenantOrm(newAssert("DELETE FROM `test` WHERE `field_b` = ? ORDER BY `field_a`", "t1"), "field_b")
+	_, err = orm.Delete(tctx, v, "ORDER BY `field_a`")
+	check(t, err)
+
+	orm = zsql.NewTenantOrm(newAssert("DELETE FROM `test`"), "field_b")
+	_, err = orm.Delete(zsql.CrossTenant(ctx), v, "")
+	check(t, err)
+}
+
+func TestTenantNumbered(t *testing.T) {
+	tctx := zsql.WithTenant(ctx, "t1")
+	orm := zsql.NewTenantOrm(zsql.Litorm{Conn: assertSql{
+		Statement: `SELECT "field_a","field_b" FROM "test" WHERE "field_b" = $1 AND ("field_a" = $2 OR "field_b" = $3) ORDER BY '$1'`,
+		Args:      []interface{}{"t1", "x", "x"},
+	}, Dialect: zsql.PostgreSQL}, "field_b")
+	check(t, orm.Select(tctx, &T{}, nil, `WHERE "field_a" = $1 OR "field_b" = $1 ORDER BY '$1'`, "x"))
+
+	orm = zsql.NewTenantOrm(zsql.Litorm{Conn: assertSql{
+		Statement: `DELETE FROM "test" WHERE "field_b" = $1 AND ("field_a" = $2)`,
+		Args:      []interface{}{"t1", "x"},
+	}, Dialect: zsql.PostgreSQL}, "field_b")
+	_, err := orm.Delete(tctx, &T{}, `WHERE "field_a" = $1`, "x")
+	check(t, err)
+}
+
+func TestTenantMethods(t *testing.T) {
+	capture := zsql.NewCapture(zsql.MySQL, zsql.SelectResult([]string{"field_a", "field_b"}, []interface{}{"a", "t1"}))
+	orm := zsql.NewTenantOrm(zsql.Litorm{Conn: capture.DB()}, "field_b")
+	tctx := zsql.WithTenant(ctx, "t1")
+
+	calls := map[string]func(ctx context.Context) error{
+		"Select":  func(ctx context.Context) error { return orm.Select(ctx, &T{}, nil) },
+		"Selects": func(ctx context.Context) error { return orm.Selects(ctx, &sliceT{}, nil) },
+		"Get":     func(ctx context.Context) error { return orm.Get(ctx, &singleKeyT{T: &T{FieldA: "a"}}, nil) },
+		"Find":    func(ctx context.Context) error { return orm.Find(ctx, &sliceT{}, zsql.From("test")) },
+		"FindOne": func(ctx context.Context) error {
+			return orm.FindOne(ctx, &T{}, zsql.From("test").Where("`field_a` = ?", "a"))
+		},
+		"Update": func(ctx context.Context) (err error) {
+			_, err = orm.Update(ctx, &T{}, nil, "")
+			return
+		},
+		"Delete": func(ctx context.Context) (err error) {
+			_, err = orm.Delete(ctx, &T{}, "WHERE `field_a` = ?", "a")
+			return
+		},
+		"DeleteByKey": func(ctx context.Context) (err error) {
+			_, err = orm.DeleteByKey(ctx, &singleKeyT{T: &T{FieldA: "a"}})
+			return
+		},
+		"Insert": func(ctx context.Context) (err error) {
+			_, err = orm.Insert(ctx, false, &T{}, nil)
+			return
+		},
+	}
+
+	for name, call := range calls {
+		if err := call(ctx); !errors.Is(err, zsql.ErrNoTenant) {
+			t.Fatal(name, err)
+		}
+		capture.Reset()
+		if err := call(tctx); err != nil {
+			t.Fatal(name, err)
+		} else if captured := capture.Captured(); len(captured) != 1 ||
+			!strings.Contains(captured[0].Statement, "`field_b`") || !containsArg(captured[0].Args, "t1") {
+			t.Fatal(name, capture.Render())
+		}
+	}
+}
+
+func containsArg(args []interface{}, v interface{}) bool {
+	for _, arg := range args {
+		if arg == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCommentConn(t *testing.T) {
+	conn := zsql.NewCommentConn(assertSql{
+		Statement: "DELETE FROM `test` /*api='get%20user',route='%2Fusers%27'*/",
+	}, zsql.TagRoute, zsql.TagApi)
+	ctx := zsql.WithSQLTags(ctx, zsql.TagRoute, "/users'", zsql.TagApi, "get user", zsql.TagRequestID, "1")
+	_, err := zsql.Litorm{Conn: conn}.Delete(ctx, &T{}, "")
+	check(t, err)
+
+	if cc := zsql.NewCommentConn(zsql.NewStmtCacher(assertSql{})); len(cc.Keys) != len(zsql.LowCardinalityTags) {
+		t.Fatal(cc.Keys)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	orm := newAssert("SELECT `field_a`,sum(`field_b`) FROM `test`")
+	orm.Strict = true
+	if err := orm.Select(ctx, &T{}, []string{"field_a", "field_b`) FROM x; --"}); !errors.Is(err, zsql.ErrUnsafeIdentifier) {
+		t.Fatal(err)
+	}
+	if err := orm.Select(ctx, &T{}, []string{"field_c"}); !errors.Is(err, zsql.ErrUnsafeIdentifier) {
+		t.Fatal(err)
+	}
+	check(t, orm.Find(ctx, &sliceT{}, zsql.From("test").Select("field_a").SelectExpr("sum(`field_b`)")))
+	if err := orm.Find(ctx, &sliceT{}, zsql.From("test").Select("sum(`field_b`)")); !errors.Is(err, zsql.ErrUnsafeIdentifier) {
+		t.Fatal(err)
+	}
+
+	if _, err := zsql.SanitizeFields(&T{}, []string{"field_a", "1=1"}); !errors.Is(err, zsql.ErrUnsafeIdentifier) {
+		t.Fatal(err)
+	}
+
+	check(t, newAssert("SELECT `field_a` FROM `te``st`").Select(ctx, &tableT{}, []string{"field_a"}))
+}
+
+type tableT struct{ T }
+
+func (tableT) TableName() string { return "te`st" }
+
+type keyT struct{ T }
+
+func (t *keyT) PrimaryKey() []string { return []string{"field_a", "field_b"} }
+
+func TestPrimaryKey(t *testing.T) {
+	v := &keyT{}
+	check(t, newAssert("SELECT `field_a`,`field_b` FROM `test` WHERE `field_a` = ? AND `field_b` = ?",
+		&v.FieldA, &v.FieldB).Get(ctx, v, nil))
+
+	_, err := newAssert("DELETE FROM `test` WHERE `field_a` = ? AND `field_b` = ?", &v.FieldA, &v.FieldB).
+		DeleteByKey(ctx, v)
+	check(t, err)
+
+	_, err = newAssert("INSERT INTO `test` (`field_a`,`field_b`) VALUES (?,?) ON DUPLICATE KEY UPDATE `field_a` = `field_a`",
+		&v.FieldA, &v.FieldB).Save(ctx, v, nil)
+	check(t, err)
+
+	_, err = zsql.Litorm{Conn: assertSql{
+		Statement: `INSERT INTO "test" ("field_a","field_b") VALUES ($1,$2) ON CONFLICT ("field_a") DO UPDATE SET "field_b" = EXCLUDED."field_b"`,
+		Args:      []interface{}{&v.FieldA, &v.FieldB},
+	}, Dialect: zsql.PostgreSQL}.Save(ctx, &singleKeyT{T: &v.T}, nil)
+	check(t, err)
+
+	if _, err = newAssert("").DeleteByKey(ctx, &T{}); !errors.Is(err, zsql.ErrNoPrimaryKey) {
+		t.Fatal(err)
+	}
+}
+
+type singleKeyT struct{ *T }
+
+func (t singleKeyT) PrimaryKey() []string { return []string{"field_a"} }
+
+func TestUpdates(t *testing.T) {
+	s := sliceT{{FieldA: "a1", FieldB: "b1"}, {FieldA: "a2", FieldB: "b2"}}
+	_, err := newAssert("UPDATE `test` SET `field_b` = CASE `field_a` WHEN ? THEN ? WHEN ? THEN ? END WHERE `field_a` IN (?,?)",
+		&s[0].FieldA, &s[0].FieldB, &s[1].FieldA, &s[1].FieldB, &s[0].FieldA, &s[1].FieldA).
+		Updates(ctx, &s, []string{"field_a"}, nil)
+	check(t, err)
+
+	one := s[:1]
+	_, err = newAssert("UPDATE `test` SET `field_b` = CASE WHEN `field_a` = ? AND `field_b` = ? THEN ? END WHERE (`field_a` = ? AND `field_b` = ?)",
+		&one[0].FieldA, &one[0].FieldB, &one[0].FieldB, &one[0].FieldA, &one[0].FieldB).
+		Updates(ctx, &one, []string{"field_a", "field_b"}, []string{"field_b"})
+	check(t, err)
+
+	_, err = zsql.Litorm{Conn: assertSql{
+		Statement: `UPDATE "test" SET "field_b" = "_v"."field_b" FROM (VALUES ((NULL::"test")."field_a",(NULL::"test")."field_b"),($1,$2),($3,$4)) AS "_v"("field_a","field_b") WHERE "test"."field_a" = "_v"."field_a"`,
+		Args:      []interface{}{&s[0].FieldA, &s[0].FieldB, &s[1].FieldA, &s[1].FieldB},
+	}, Dialect: zsql.PostgreSQL}.Updates(ctx, &s, []string{"field_a"}, nil)
+	check(t, err)
+
+	capture := zsql.NewCapture(zsql.SQLite, func(string, []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{RowsAffected: 1}
+	})
+	result, err := zsql.Litorm{Conn: capture.DB(), Dialect: zsql.SQLite, ChunkSize: 1}.Updates(ctx, &s, []string{"field_a"}, nil)
+	if n, _ := result.RowsAffected(); err != nil || n != 2 || len(capture.Captured()) != 2 {
+		t.Fatal(err, n)
+	}
+
+	defer func(n int) { zsql.MaxPlaceholders = n }(zsql.MaxPlaceholders)
+	zsql.MaxPlaceholders = 5
+	capture.Reset()
+	if _, err = (zsql.Litorm{Conn: capture.DB(), Dialect: zsql.SQLite}).Updates(ctx, &s, []string{"field_a"}, nil); err != nil || len(capture.Captured()) != 2 {
+		t.Fatal(err, capture.Render())
+	}
+}
@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+const (
+	StatusPending = iota
+	StatusSent
+	StatusDead
+)
+
+var (
+	ErrNoSession     = errors.New("outbox publish without session transaction")
+	ErrNotConfigured = errors.New("outbox database not configured")
+)
+
+type (
+	Publisher interface {
+		Publish(ctx context.Context, topic string, payload []byte) error
+	}
+
+	PublisherFunc func(ctx context.Context, topic string, payload []byte) error
+
+	// Outbox stores messages in table with columns:
+	// id, topic, payload, status, attempts, error, available_at, created_at, updated_at
+	Outbox struct {
+		DB      zsql.DB
+		Table   string
+		Dialect zsql.Dialect
+	}
+
+	Message struct {
+		ID          int64
+		Topic       string
+		Payload     []byte
+		Status      int
+		Attempts    int
+		Error       string
+		AvailableAt time.Time
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+
+		table string
+	}
+
+	messages struct {
+		table string
+		list  []*Message
+	}
+)
+
+// DefaultOutbox used by package Publish, its DB must be set before publishing.
+var DefaultOutbox = &Outbox{Table: "outbox"}
+
+func Publish(ctx context.Context, topic string, payload []byte) error {
+	return DefaultOutbox.Publish(ctx, topic, payload)
+}
+
+func (fn PublisherFunc) Publish(ctx context.Context, topic string, payload []byte) error {
+	return fn(ctx, topic, payload)
+}
+
+func (m *Message) TableName() string { return m.table }
+
+func (m *Message) FieldMapping(dst map[string]interface{}) {
+	dst["id"] = &m.ID
+	dst["topic"] = &m.Topic
+	dst["payload"] = &m.Payload
+	dst["status"] = &m.Status
+	dst["attempts"] = &m.Attempts
+	dst["error"] = &m.Error
+	dst["available_at"] = &m.AvailableAt
+	dst["created_at"] = &m.CreatedAt
+	dst["updated_at"] = &m.UpdatedAt
+}
+
+func (ms *messages) Iterate(f func(v interface{}, alloc bool) (next bool)) {
+	for i := 0; ; i++ {
+		if alloc := i >= len(ms.list); !alloc {
+			if !f(ms.list[i], alloc) {
+				return
+			}
+		} else if m := (&Message{table: ms.table}); f(m, alloc) {
+			ms.list = append(ms.list, m)
+		} else {
+			return
+		}
+	}
+}
+
+func (o *Outbox) orm() zsql.Litorm {
+	return zsql.Litorm{Conn: zsql.SessionConn(o.DB), Dialect: o.Dialect}
+}
+
+func (o *Outbox) Publish(ctx context.Context, topic string, payload []byte) (err error) {
+	if o.DB == nil {
+		return ErrNotConfigured
+	} else if !zsql.InSession(ctx, o.DB) {
+		return ErrNoSession
+	}
+	now := time.Now()
+	_, err = o.orm().Insert(ctx, false, &Message{
+		table:       o.Table,
+		Topic:       topic,
+		Payload:     payload,
+		Status:      StatusPending,
+		AvailableAt: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, []string{"topic", "payload", "status", "attempts", "error", "available_at", "created_at", "updated_at"})
+	return
+}
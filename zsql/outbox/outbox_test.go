@@ -0,0 +1,77 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-zing/gozz-kit/zsql"
+	"github.com/go-zing/gozz-kit/zsql/outbox"
+)
+
+var ctx = context.Background()
+
+func newCapture() *zsql.Capture {
+	return zsql.NewCapture(zsql.MySQL, zsql.SelectResult(
+		[]string{"attempts", "available_at", "created_at", "error", "id", "payload", "status", "topic", "updated_at"},
+		[]interface{}{int64(0), time.Now(), time.Now(), "", int64(1), []byte("{}"), int64(0), "topic", time.Now()}))
+}
+
+func TestOutbox(t *testing.T) {
+	capture := newCapture()
+	db := capture.DB()
+	box := &outbox.Outbox{DB: db, Table: "outbox"}
+
+	if err := box.Publish(ctx, "topic", []byte("{}")); !errors.Is(err, outbox.ErrNoSession) {
+		t.Fatal(err)
+	}
+	if err := zsql.WithSessionTx(ctx, db, func(ctx context.Context) error {
+		return box.Publish(ctx, "topic", []byte("{}"))
+	}); err != nil || len(capture.Captured()) != 3 {
+		t.Fatal(err, capture.Render())
+	}
+
+	capture.Reset()
+	published := ""
+	relay := &outbox.Relay{Outbox: box, Publisher: outbox.PublisherFunc(func(ctx context.Context, topic string, payload []byte) error {
+		published = topic
+		return nil
+	})}
+	if n, err := relay.RelayOnce(ctx); err != nil || n != 1 || published != "topic" {
+		t.Fatal(n, err, published)
+	}
+
+	captured := capture.Captured()
+	if len(captured) != 4 || captured[2].Args[0] != int64(outbox.StatusSent) {
+		t.Fatal(capture.Render())
+	}
+}
+
+func TestRelayFailure(t *testing.T) {
+	capture := newCapture()
+	box := &outbox.Outbox{DB: capture.DB(), Table: "outbox"}
+
+	published := 0
+	relay := &outbox.Relay{Outbox: box, Interval: time.Millisecond * 20, Publisher: outbox.PublisherFunc(
+		func(ctx context.Context, topic string, payload []byte) error { published++; return errors.New("down") })}
+
+	start := time.Now()
+	if n, err := relay.RelayOnce(ctx); err != nil || n != 0 {
+		t.Fatal(n, err)
+	}
+	if available := capture.Captured()[2].Args[3].(time.Time); available.Before(start.Add(outbox.DefaultRetryDelay)) {
+		t.Fatal(available)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*50)
+	defer cancel()
+	published = 0
+	if err := relay.Run(ctx); !errors.Is(err, context.DeadlineExceeded) || published > 5 {
+		t.Fatal(err, published)
+	}
+
+	if err := outbox.Publish(ctx, "topic", nil); !errors.Is(err, outbox.ErrNotConfigured) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,110 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+var DefaultRetryDelay = 10 * time.Second
+
+type Relay struct {
+	Outbox      *Outbox
+	Publisher   Publisher
+	BatchSize   int
+	Interval    time.Duration
+	MaxAttempts int
+	// delay multiplied by attempts before retrying failed message, DefaultRetryDelay if zero
+	RetryDelay time.Duration
+	OnError    func(err error)
+}
+
+func (r *Relay) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	for {
+		n, err := r.RelayOnce(ctx)
+		if err != nil && r.OnError != nil {
+			r.OnError(err)
+		}
+		// relay next batch at once only when messages were sent,
+		// otherwise wait for interval to avoid busy looping on publisher failure
+		if n > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// RelayOnce claims one batch of pending messages, publishes them and returns count of sent.
+// messages are locked in claiming transaction until they are marked.
+func (r *Relay) RelayOnce(ctx context.Context) (n int, err error) {
+	o := r.Outbox
+	orm := o.orm()
+
+	batch := r.BatchSize
+	if batch <= 0 {
+		batch = 100
+	}
+
+	retryDelay := r.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = DefaultRetryDelay
+	}
+
+	condition := &zsql.SqlBuilder{Dialect: o.Dialect}
+	condition.WriteString("WHERE ")
+	condition.WriteFields([]string{"status", "available_at"}, true, "", " = ? AND ")
+	condition.WriteString(" <= ? ORDER BY ")
+	condition.WriteFields([]string{"id"}, true, "", "")
+	condition.WriteString(" LIMIT ?")
+	if o.Dialect != zsql.SQLite {
+		condition.WriteString(" FOR UPDATE SKIP LOCKED")
+	}
+
+	update := &zsql.SqlBuilder{Dialect: o.Dialect}
+	update.WriteString("WHERE ")
+	update.WriteFields([]string{"id"}, true, " = ?", "")
+
+	err = zsql.WithSessionTx(ctx, o.DB, func(ctx context.Context) (err error) {
+		ms := &messages{table: o.Table}
+		now := time.Now()
+		if err = orm.Selects(ctx, ms, nil, condition.String(), StatusPending, now, batch); err != nil {
+			return
+		}
+
+		for _, m := range ms.list {
+			if perr := r.Publisher.Publish(ctx, m.Topic, m.Payload); perr != nil {
+				m.Attempts++
+				m.Error = perr.Error()
+				m.AvailableAt = now.Add(retryDelay * time.Duration(m.Attempts))
+				if r.MaxAttempts > 0 && m.Attempts >= r.MaxAttempts {
+					m.Status = StatusDead
+				}
+			} else {
+				m.Status, m.Error = StatusSent, ""
+				n++
+			}
+
+			m.UpdatedAt = now
+			if _, err = orm.Update(ctx, m, []string{"status", "attempts", "error", "available_at", "updated_at"},
+				update.String(), m.ID); err != nil {
+				return
+			}
+		}
+		return
+	})
+	if err != nil {
+		n = 0
+	}
+	return
+}
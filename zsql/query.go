@@ -0,0 +1,362 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+)
+
+type (
+	// Query is immutable select statement builder.
+	// Every method returns a modified copy and leaves receiver untouched.
+	Query struct {
+		ctes      []cte
+		recursive bool
+		distinct  bool
+		columns   []column
+		from      clause
+		joins     []clause
+		where     []clause
+		groupBy   []column
+		having    []clause
+		orderBy   []column
+		limit     int
+		offset    int
+	}
+
+	cte struct {
+		name  string
+		query Query
+	}
+
+	// column is name checked in strict mode, or Expr written verbatim
+	column struct {
+		name string
+		expr bool
+		desc bool
+	}
+
+	// clause is expression with args, positional args of Query type are rendered as subquery
+	clause struct {
+		expr string
+		args []interface{}
+	}
+)
+
+func From(table string) Query { return Query{from: clause{expr: table}} }
+
+// FromQuery selects from subquery with alias.
+func FromQuery(q Query, alias string) Query {
+	return Query{from: clause{expr: "? AS " + alias, args: []interface{}{q}}}
+}
+
+func appendClause(list []clause, expr string, args []interface{}) []clause {
+	return append(append(make([]clause, 0, len(list)+1), list...), clause{expr: expr, args: args})
+}
+
+func appendColumns(list []column, expr bool, names ...string) []column {
+	list = append(make([]column, 0, len(list)+len(names)), list...)
+	for _, name := range names {
+		list = append(list, column{name: name, expr: expr})
+	}
+	return list
+}
+
+func exprStrings(exprs []Expr) []string {
+	list := make([]string, len(exprs))
+	for i, expr := range exprs {
+		list[i] = string(expr)
+	}
+	return list
+}
+
+func (q Query) With(name string, query Query) Query {
+	q.ctes = append(append(make([]cte, 0, len(q.ctes)+1), q.ctes...), cte{name: name, query: query})
+	return q
+}
+
+func (q Query) WithRecursive(name string, query Query) Query {
+	q = q.With(name, query)
+	q.recursive = true
+	return q
+}
+
+func (q Query) Distinct() Query { q.distinct = true; return q }
+
+// Select appends columns to select, all columns selected if none.
+func (q Query) Select(columns ...string) Query {
+	q.columns = appendColumns(q.columns, false, columns...)
+	return q
+}
+
+// SelectExpr appends expressions to select, which are written verbatim in strict mode.
+func (q Query) SelectExpr(exprs ...Expr) Query {
+	q.columns = appendColumns(q.columns, true, exprStrings(exprs)...)
+	return q
+}
+
+// Join appends join clause such as "LEFT JOIN b ON b.a_id = a.id".
+func (q Query) Join(join string, args ...interface{}) Query {
+	q.joins = appendClause(q.joins, join, args)
+	return q
+}
+
+func (q Query) InnerJoin(table, on string, args ...interface{}) Query {
+	return q.Join("INNER JOIN "+table+" ON "+on, args...)
+}
+
+func (q Query) LeftJoin(table, on string, args ...interface{}) Query {
+	return q.Join("LEFT JOIN "+table+" ON "+on, args...)
+}
+
+// Where appends condition joined with AND.
+func (q Query) Where(condition string, args ...interface{}) Query {
+	q.where = appendClause(q.where, condition, args)
+	return q
+}
+
+func (q Query) GroupBy(columns ...string) Query {
+	q.groupBy = appendColumns(q.groupBy, false, columns...)
+	return q
+}
+
+// Having appends condition of groups joined with AND.
+func (q Query) Having(condition string, args ...interface{}) Query {
+	q.having = appendClause(q.having, condition, args)
+	return q
+}
+
+func (q Query) OrderBy(orders ...string) Query {
+	q.orderBy = appendColumns(q.orderBy, false, orders...)
+	return q
+}
+
+// OrderByColumn appends order of column checked in strict mode, such as sort column from request.
+func (q Query) OrderByColumn(name string, desc bool) Query {
+	q.orderBy = append(append(make([]column, 0, len(q.orderBy)+1), q.orderBy...), column{name: name, desc: desc})
+	return q
+}
+
+// OrderByExpr appends order expressions such as "`a` DESC", which are written verbatim in strict mode.
+func (q Query) OrderByExpr(exprs ...Expr) Query {
+	q.orderBy = appendColumns(q.orderBy, true, exprStrings(exprs)...)
+	return q
+}
+
+func (q Query) Limit(n int) Query { q.limit = n; return q }
+
+func (q Query) Offset(n int) Query { q.offset = n; return q }
+
+// Build writes statement into builder and returns args in placeholders order.
+func (q Query) Build(bd *SqlBuilder) (args []interface{}) {
+	if len(q.ctes) > 0 {
+		bd.WriteString("WITH ")
+		if q.recursive {
+			bd.WriteString("RECURSIVE ")
+		}
+		for i, c := range q.ctes {
+			if i > 0 {
+				bd.WriteRune(',')
+			}
+			bd.writeName(c.name)
+			bd.WriteString(" AS (")
+			args = append(args, c.query.Build(bd)...)
+			bd.WriteString(") ")
+		}
+	}
+
+	bd.WriteString("SELECT ")
+	if q.distinct {
+		bd.WriteString("DISTINCT ")
+	}
+	if len(q.columns) == 0 {
+		bd.WriteRune('*')
+	}
+	bd.writeNames(q.columns)
+
+	bd.WriteString(" FROM ")
+	if len(q.from.args) > 0 {
+		bd.writeClause(q.from, &args)
+	} else {
+		bd.writeName(q.from.expr)
+	}
+
+	for _, join := range q.joins {
+		bd.WriteRune(' ')
+		bd.writeClause(join, &args)
+	}
+	bd.writeConditions(" WHERE ", q.where, &args)
+	if len(q.groupBy) > 0 {
+		bd.WriteString(" GROUP BY ")
+		bd.writeNames(q.groupBy)
+	}
+	bd.writeConditions(" HAVING ", q.having, &args)
+	if len(q.orderBy) > 0 {
+		bd.WriteString(" ORDER BY ")
+		bd.writeNames(q.orderBy)
+	}
+	if q.limit > 0 {
+		bd.WriteString(" LIMIT ")
+		bd.WriteString(strconv.Itoa(q.limit))
+	} else if q.offset > 0 && bd.Dialect != PostgreSQL {
+		// MySQL and SQLite require LIMIT before OFFSET
+		bd.WriteString(bd.Dialect.maxLimit())
+	}
+	if q.offset > 0 {
+		bd.WriteString(" OFFSET ")
+		bd.WriteString(strconv.Itoa(q.offset))
+	}
+	return
+}
+
+func (d Dialect) maxLimit() string {
+	if d == SQLite {
+		return " LIMIT -1"
+	}
+	return " LIMIT 18446744073709551615"
+}
+
+// Render returns statement with dialect placeholders and args.
+func (q Query) Render(dialect Dialect) (string, []interface{}, error) {
+	bd := &SqlBuilder{Dialect: dialect}
+	return bd.Rebind(q.Build(bd))
+}
+
+// write name verbatim, or check as identifier in strict mode
+func (bd *SqlBuilder) writeName(name string) {
+	if bd.Strict {
+		bd.writeStrictField(name)
+	} else {
+		bd.WriteString(name)
+	}
+}
+
+func (bd *SqlBuilder) writeNames(columns []column) {
+	for i, c := range columns {
+		if i > 0 {
+			bd.WriteRune(',')
+		}
+		if c.expr {
+			bd.WriteString(c.name)
+		} else {
+			bd.writeName(c.name)
+		}
+		if c.desc {
+			bd.WriteString(" DESC")
+		}
+	}
+}
+
+func (bd *SqlBuilder) writeConditions(keyword string, conditions []clause, args *[]interface{}) {
+	for i, c := range conditions {
+		if i == 0 {
+			bd.WriteString(keyword)
+		} else {
+			bd.WriteString(" AND ")
+		}
+		if len(conditions) > 1 {
+			bd.WriteRune('(')
+		}
+		bd.writeClause(c, args)
+		if len(conditions) > 1 {
+			bd.WriteRune(')')
+		}
+	}
+}
+
+// write clause with positional args of Query type spliced as subquery
+func (bd *SqlBuilder) writeClause(c clause, args *[]interface{}) {
+	last, index := 0, 0
+	_ = bd.Dialect.walkPlaceholders(c.expr, func(start, end int, name string) error {
+		if len(name) > 0 || index >= len(c.args) {
+			return nil
+		}
+		if sub, ok := c.args[index].(Query); ok {
+			bd.WriteString(c.expr[last:start])
+			bd.WriteRune('(')
+			*args = append(*args, sub.Build(bd)...)
+			bd.WriteRune(')')
+			last = end
+		} else {
+			*args = append(*args, c.args[index])
+		}
+		index++
+		return nil
+	})
+	bd.WriteString(c.expr[last:])
+	*args = append(*args, c.args[index:]...)
+}
+
+// Find selects rows of query into models, columns are matched to model field mapping by name.
+func (orm Litorm) Find(ctx context.Context, models ModelIterator, q Query) (err error) {
+	if _, err = orm.find(ctx, models, q); err == sql.ErrNoRows {
+		err = nil
+	}
+	return
+}
+
+// FindOne selects first row of query into model, returns sql.ErrNoRows if none.
+func (orm Litorm) FindOne(ctx context.Context, model Model, q Query) (err error) {
+	_, err = orm.find(ctx, modelItem{Model: model}, q)
+	return
+}
+
+func (orm Litorm) FindMaps(ctx context.Context, q Query) ([]map[string]interface{}, error) {
+	bd := orm.builder()
+	args := q.Build(bd)
+	if bd.err != nil {
+		return nil, bd.err
+	}
+	return orm.QueryMaps(ctx, bd.String(), args...)
+}
+
+func (orm Litorm) find(ctx context.Context, models ModelIterator, q Query) (n int, err error) {
+	bd := orm.builder()
+	rows, err := orm.query(ctx, bd, q.Build(bd))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return
+	}
+
+	mapping := make(FieldMapping, len(columns))
+	dst := make([]interface{}, 0, len(columns))
+	models.Iterate(func(v interface{}, alloc bool) (next bool) {
+		model, ok := v.(Model)
+		if !ok {
+			err = ErrInvalidModelsIterator
+			return
+		} else if !rows.Next() {
+			return
+		}
+		model.FieldMapping(mapping)
+		for _, column := range columns {
+			if _, ok := mapping[column]; !ok {
+				mapping[column] = new(interface{})
+			}
+		}
+		if err = orm.scan(rows, model, mapping, columns, &dst); err != nil {
+			return
+		}
+		dst, n = dst[:0], n+1
+		return true
+	})
+
+	if err == nil {
+		err = rows.Err()
+	}
+	if err == nil && n == 0 {
+		err = sql.ErrNoRows
+	}
+	return
+}
+
+func (q Query) String() string {
+	bd := new(SqlBuilder)
+	q.Build(bd)
+	return bd.String()
+}
@@ -0,0 +1,93 @@
+package zsql_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestQuery(t *testing.T) {
+	active := zsql.From("orders").Select("user_id").Where("status = ?", "paid")
+	base := zsql.From("users u").
+		Select("u.id", "u.name", "COUNT(o.id) AS n").
+		LeftJoin("orders o", "o.user_id = u.id AND o.created > ?", 10)
+	q := base.
+		With("recent", active.Limit(10)).
+		Where("u.id IN ?", active).
+		Where("u.name <> ?", "x").
+		GroupBy("u.id", "u.name").
+		Having("COUNT(o.id) > ?", 1).
+		OrderBy("n DESC").
+		Limit(20).
+		Offset(40)
+
+	statement, args, err := q.Render(zsql.PostgreSQL)
+	want := "WITH recent AS (SELECT user_id FROM orders WHERE status = $1 LIMIT 10) " +
+		"SELECT u.id,u.name,COUNT(o.id) AS n FROM users u LEFT JOIN orders o ON o.user_id = u.id AND o.created > $2 " +
+		"WHERE (u.id IN (SELECT user_id FROM orders WHERE status = $3)) AND (u.name <> $4) " +
+		"GROUP BY u.id,u.name HAVING COUNT(o.id) > $5 ORDER BY n DESC LIMIT 20 OFFSET 40"
+	if err != nil || statement != want {
+		t.Fatalf("want %q got %q %v", want, statement, err)
+	}
+	if wantArgs := []interface{}{"paid", 10, "paid", "x", 1}; !reflect.DeepEqual(args, wantArgs) {
+		t.Fatal(args)
+	}
+
+	if statement, _, _ = base.Render(zsql.MySQL); statement != "SELECT u.id,u.name,COUNT(o.id) AS n FROM users u LEFT JOIN orders o ON o.user_id = u.id AND o.created > ?" {
+		t.Fatal("base query modified", statement)
+	}
+
+	if statement, _, _ = zsql.FromQuery(active, "a").Distinct().Render(zsql.MySQL); statement != "SELECT DISTINCT * FROM (SELECT user_id FROM orders WHERE status = ?) AS a" {
+		t.Fatal(statement)
+	}
+}
+
+func TestFind(t *testing.T) {
+	capture := zsql.NewCapture(zsql.SQLite, func(string, []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{
+			Columns: []string{"field_a", "n"},
+			Rows:    [][]interface{}{{"a", int64(1)}, {"b", int64(2)}},
+		}
+	})
+	orm := zsql.Litorm{Conn: capture.DB(), Dialect: zsql.SQLite}
+	q := zsql.From("test").Select("field_a", "COUNT(*) AS n").GroupBy("field_a")
+
+	var list sliceT
+	if err := orm.Find(ctx, &list, q); err != nil || len(list) != 2 || list[1].FieldA != "b" {
+		t.Fatal(list, err)
+	}
+
+	v := &T{}
+	if err := orm.FindOne(ctx, v, q); err != nil || v.FieldA != "a" {
+		t.Fatal(v, err)
+	}
+
+	maps, err := orm.FindMaps(ctx, q)
+	if err != nil || len(maps) != 2 || maps[1]["n"] != int64(2) {
+		t.Fatal(maps, err)
+	}
+	if captured := capture.Captured(); captured[0].Statement != "SELECT field_a,COUNT(*) AS n FROM test GROUP BY field_a" {
+		t.Fatal(captured)
+	}
+}
+
+func TestQueryOrderOffset(t *testing.T) {
+	q := zsql.From("test").OrderByColumn("field_a", true).OrderByColumn("field_b", false).Offset(10)
+	for dialect, want := range map[zsql.Dialect]string{
+		zsql.MySQL:      "SELECT * FROM `test` ORDER BY `field_a` DESC,`field_b` LIMIT 18446744073709551615 OFFSET 10",
+		zsql.SQLite:     "SELECT * FROM \"test\" ORDER BY \"field_a\" DESC,\"field_b\" LIMIT -1 OFFSET 10",
+		zsql.PostgreSQL: "SELECT * FROM \"test\" ORDER BY \"field_a\" DESC,\"field_b\" OFFSET 10",
+	} {
+		bd := &zsql.SqlBuilder{Dialect: dialect, Strict: true}
+		if q.Build(bd); bd.Err() != nil || bd.String() != want {
+			t.Fatal(bd.String(), bd.Err())
+		}
+	}
+
+	bd := &zsql.SqlBuilder{Strict: true}
+	if zsql.From("test").OrderByColumn("field_a DESC", false).Build(bd); !errors.Is(bd.Err(), zsql.ErrUnsafeIdentifier) {
+		t.Fatal(bd.String())
+	}
+}
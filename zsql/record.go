@@ -0,0 +1,447 @@
+package zsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrReplayDrift = errors.New("replay drift")
+
+type (
+	RecordValue struct{ Value interface{} }
+
+	RecordEntry struct {
+		Kind         string          `json:"kind"`
+		Statement    string          `json:"statement,omitempty"`
+		Args         []RecordValue   `json:"args,omitempty"`
+		Columns      []string        `json:"columns,omitempty"`
+		Types        []string        `json:"types,omitempty"`
+		Rows         [][]RecordValue `json:"rows,omitempty"`
+		LastInsertId int64           `json:"last_insert_id,omitempty"`
+		RowsAffected int64           `json:"rows_affected,omitempty"`
+		Error        string          `json:"error,omitempty"`
+	}
+
+	Recorder struct {
+		connector driver.Connector
+		mu        sync.Mutex
+		entries   []RecordEntry
+	}
+
+	Replayer struct {
+		MatchStatement bool
+
+		mu      sync.Mutex
+		entries []RecordEntry
+		used    []bool
+		offset  int
+		drifts  []string
+	}
+
+	recordConnector struct {
+		driver.Connector
+		recorder *Recorder
+	}
+
+	recordConn struct {
+		driver.Conn
+		recorder *Recorder
+	}
+
+	recordStmt struct {
+		driver.Stmt
+		statement string
+		recorder  *Recorder
+	}
+
+	recordTx struct {
+		driver.Tx
+		recorder *Recorder
+	}
+)
+
+func (v RecordValue) MarshalJSON() ([]byte, error) {
+	switch value := v.Value.(type) {
+	case nil:
+		return []byte("null"), nil
+	case int64:
+		return json.Marshal(map[string]int64{"int": value})
+	case float64:
+		return json.Marshal(map[string]float64{"float": value})
+	case bool:
+		return json.Marshal(map[string]bool{"bool": value})
+	case string:
+		return json.Marshal(map[string]string{"string": value})
+	case []byte:
+		return json.Marshal(map[string]string{"bytes": base64.StdEncoding.EncodeToString(value)})
+	case time.Time:
+		return json.Marshal(map[string]string{"time": value.Format(time.RFC3339Nano)})
+	default:
+		return json.Marshal(map[string]string{"string": fmt.Sprint(value)})
+	}
+}
+
+func (v *RecordValue) UnmarshalJSON(data []byte) (err error) {
+	var m map[string]json.RawMessage
+	if err = json.Unmarshal(data, &m); err != nil || m == nil {
+		v.Value = nil
+		return
+	}
+	for typ, raw := range m {
+		switch typ {
+		case "int":
+			var n int64
+			err, v.Value = json.Unmarshal(raw, &n), n
+		case "float":
+			var f float64
+			err, v.Value = json.Unmarshal(raw, &f), f
+		case "bool":
+			var b bool
+			err, v.Value = json.Unmarshal(raw, &b), b
+		case "string":
+			var s string
+			err, v.Value = json.Unmarshal(raw, &s), s
+		case "bytes":
+			var s string
+			if err = json.Unmarshal(raw, &s); err == nil {
+				v.Value, err = base64.StdEncoding.DecodeString(s)
+			}
+		case "time":
+			var s string
+			if err = json.Unmarshal(raw, &s); err == nil {
+				v.Value, err = time.Parse(time.RFC3339Nano, s)
+			}
+		default:
+			err = fmt.Errorf("unknown record value type %s", typ)
+		}
+	}
+	return
+}
+
+func recordValues(values []interface{}) []RecordValue {
+	if len(values) == 0 {
+		return nil
+	}
+	ret := make([]RecordValue, len(values))
+	for i, v := range values {
+		if b, ok := v.([]byte); ok {
+			v = append([]byte(nil), b...)
+		}
+		ret[i] = RecordValue{Value: v}
+	}
+	return ret
+}
+
+func plainValues(values []RecordValue) []interface{} {
+	ret := make([]interface{}, len(values))
+	for i, v := range values {
+		ret[i] = v.Value
+	}
+	return ret
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func NewRecorder(connector driver.Connector) *Recorder { return &Recorder{connector: connector} }
+
+func (r *Recorder) DB() *sql.DB {
+	return sql.OpenDB(recordConnector{Connector: r.connector, recorder: r})
+}
+
+func (r *Recorder) Entries() []RecordEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordEntry(nil), r.entries...)
+}
+
+func (r *Recorder) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.Entries())
+}
+
+func (r *Recorder) record(entry RecordEntry) {
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+}
+
+func (c recordConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &recordConn{Conn: conn, recorder: c.recorder}, nil
+}
+
+func (c *recordConn) Prepare(statement string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), statement)
+}
+
+func (c *recordConn) PrepareContext(ctx context.Context, statement string) (stmt driver.Stmt, err error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, statement)
+	} else {
+		stmt, err = c.Conn.Prepare(statement)
+	}
+	if err != nil {
+		return
+	}
+	return &recordStmt{Stmt: stmt, statement: statement, recorder: c.recorder}, nil
+}
+
+func (c *recordConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *recordConn) BeginTx(ctx context.Context, opts driver.TxOptions) (tx driver.Tx, err error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+	if c.recorder.record(RecordEntry{Kind: "begin", Error: errorString(err)}); err != nil {
+		return
+	}
+	return &recordTx{Tx: tx, recorder: c.recorder}, nil
+}
+
+func (c *recordConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *recordConn) ExecContext(ctx context.Context, statement string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	res, err := execer.ExecContext(ctx, statement, args)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	return c.recorder.recordExec(statement, namedValues(args), res, err)
+}
+
+func (c *recordConn) QueryContext(ctx context.Context, statement string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	rows, err := queryer.QueryContext(ctx, statement, args)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	return c.recorder.recordQuery(statement, namedValues(args), rows, err)
+}
+
+func (s *recordStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (res driver.Result, err error) {
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = execer.ExecContext(ctx, args)
+	} else {
+		res, err = s.Stmt.Exec(namedDriverValues(args))
+	}
+	return s.recorder.recordExec(s.statement, namedValues(args), res, err)
+}
+
+func (s *recordStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(namedDriverValues(args))
+	}
+	return s.recorder.recordQuery(s.statement, namedValues(args), rows, err)
+}
+
+func (s *recordStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (tx *recordTx) Commit() error {
+	err := tx.Tx.Commit()
+	tx.recorder.record(RecordEntry{Kind: "commit", Error: errorString(err)})
+	return err
+}
+
+func (tx *recordTx) Rollback() error {
+	err := tx.Tx.Rollback()
+	tx.recorder.record(RecordEntry{Kind: "rollback", Error: errorString(err)})
+	return err
+}
+
+func namedDriverValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i := range args {
+		values[i] = args[i].Value
+	}
+	return values
+}
+
+func (r *Recorder) recordExec(statement string, args []interface{}, res driver.Result, err error) (driver.Result, error) {
+	entry := RecordEntry{Kind: "exec", Statement: statement, Args: recordValues(args), Error: errorString(err)}
+	if err == nil {
+		entry.LastInsertId, _ = res.LastInsertId()
+		entry.RowsAffected, _ = res.RowsAffected()
+	}
+	r.record(entry)
+	return res, err
+}
+
+func (r *Recorder) recordQuery(statement string, args []interface{}, rows driver.Rows, err error) (driver.Rows, error) {
+	entry := RecordEntry{Kind: "query", Statement: statement, Args: recordValues(args)}
+	if err != nil {
+		entry.Error = err.Error()
+		r.record(entry)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := &hookRows{columns: rows.Columns()}
+	if typer, ok := rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		for i := range ret.columns {
+			ret.types = append(ret.types, typer.ColumnTypeDatabaseTypeName(i))
+		}
+	}
+
+	dst := make([]driver.Value, len(ret.columns))
+	for {
+		if err = rows.Next(dst); err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			break
+		}
+		row := recordValues(driverValues(dst))
+		entry.Rows = append(entry.Rows, row)
+		ret.rows = append(ret.rows, plainValues(row))
+	}
+
+	entry.Columns, entry.Types, entry.Error = ret.columns, ret.types, errorString(err)
+	if r.record(entry); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	var entries []RecordEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return &Replayer{entries: entries, used: make([]bool, len(entries))}, nil
+}
+
+func (r *Replayer) DB() *sql.DB { return openHookDB(r) }
+
+// Drift returns error with all drifts detected and entries not replayed.
+func (r *Replayer) Drift() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	drifts := append([]string(nil), r.drifts...)
+	for i, used := range r.used {
+		if !used {
+			drifts = append(drifts, fmt.Sprintf("unreplayed %s %s", r.entries[i].Kind, r.entries[i].Statement))
+		}
+	}
+	if len(drifts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrReplayDrift, strings.Join(drifts, ". "))
+}
+
+func encodeArgs(args []RecordValue) string {
+	data, _ := json.Marshal(args)
+	return string(bytes.TrimSpace(data))
+}
+
+func (r *Replayer) match(entry RecordEntry, i int) bool {
+	if r.used[i] || r.entries[i].Kind != entry.Kind || r.entries[i].Statement != entry.Statement {
+		return false
+	}
+	return encodeArgs(r.entries[i].Args) == encodeArgs(entry.Args)
+}
+
+func (r *Replayer) replay(kind, statement string, args []interface{}) (RecordEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := RecordEntry{Kind: kind, Statement: statement, Args: recordValues(args)}
+	if r.MatchStatement {
+		for i := range r.entries {
+			if r.match(entry, i) {
+				r.used[i] = true
+				return r.entries[i], nil
+			}
+		}
+	} else if r.offset < len(r.entries) && r.match(entry, r.offset) {
+		r.used[r.offset] = true
+		r.offset++
+		return r.entries[r.offset-1], nil
+	}
+
+	drift := fmt.Sprintf("unexpected %s %s %s", kind, statement, encodeArgs(entry.Args))
+	if !r.MatchStatement && r.offset < len(r.entries) {
+		expected := r.entries[r.offset]
+		drift += fmt.Sprintf(" expecting %s %s %s", expected.Kind, expected.Statement, encodeArgs(expected.Args))
+	}
+	r.drifts = append(r.drifts, drift)
+	return entry, fmt.Errorf("%w: %s", ErrReplayDrift, drift)
+}
+
+func (r *Replayer) replayError(kind string) error {
+	entry, err := r.replay(kind, "", nil)
+	if err == nil && len(entry.Error) > 0 {
+		err = errors.New(entry.Error)
+	}
+	return err
+}
+
+func (r *Replayer) begin() error { return r.replayError("begin") }
+
+func (r *Replayer) commit() error { return r.replayError("commit") }
+
+func (r *Replayer) rollback() error { return r.replayError("rollback") }
+
+func (r *Replayer) exec(statement string, args []interface{}) (driver.Result, error) {
+	entry, err := r.replay("exec", statement, args)
+	if err != nil {
+		return nil, err
+	} else if len(entry.Error) > 0 {
+		return nil, errors.New(entry.Error)
+	}
+	return rowsResult{lastInsertId: entry.LastInsertId, rowsAffected: entry.RowsAffected}, nil
+}
+
+func (r *Replayer) query(statement string, args []interface{}) (driver.Rows, error) {
+	entry, err := r.replay("query", statement, args)
+	if err != nil {
+		return nil, err
+	} else if len(entry.Error) > 0 {
+		return nil, errors.New(entry.Error)
+	}
+	rows := &hookRows{columns: entry.Columns, types: entry.Types}
+	for _, row := range entry.Rows {
+		rows.rows = append(rows.rows, plainValues(row))
+	}
+	return rows, nil
+}
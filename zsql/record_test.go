@@ -0,0 +1,77 @@
+package zsql_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestRecordReplay(t *testing.T) {
+	capture := zsql.NewCapture(zsql.MySQL, zsql.SelectResult([]string{"field_a", "field_b"}, []interface{}{[]byte("a"), "b"}))
+
+	run := func(db zsql.Conn, value string) (t T, err error) {
+		orm := zsql.Litorm{Conn: db}
+		if _, err = orm.Insert(ctx, false, &T{FieldA: value}, []string{"field_a"}); err != nil {
+			return
+		}
+		err = orm.Select(ctx, &t, nil, "WHERE field_a = ?", value)
+		return
+	}
+
+	recorder := zsql.NewRecorder(capture.Connector())
+	if _, err := run(recorder.DB(), "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := new(bytes.Buffer)
+	if err := recorder.Save(golden); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := zsql.NewReplayer(bytes.NewReader(golden.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := run(replayer.DB(), "a"); err != nil || got.FieldA != "a" || got.FieldB != "b" {
+		t.Fatal(got, err)
+	}
+	if err = replayer.Drift(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, _ = zsql.NewReplayer(bytes.NewReader(golden.Bytes()))
+	if _, err = run(replayer.DB(), "b"); !errors.Is(err, zsql.ErrReplayDrift) {
+		t.Fatal(err)
+	}
+	if err = replayer.Drift(); !errors.Is(err, zsql.ErrReplayDrift) {
+		t.Fatal(err)
+	}
+}
+
+func TestReplayMatchStatement(t *testing.T) {
+	recorder := zsql.NewRecorder(zsql.NewCapture(zsql.MySQL, nil).Connector())
+	db := recorder.DB()
+	for _, statement := range []string{"DELETE FROM a", "DELETE FROM b"} {
+		if _, err := db.ExecContext(context.Background(), statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	golden := new(bytes.Buffer)
+	_ = recorder.Save(golden)
+	replayer, _ := zsql.NewReplayer(golden)
+	replayer.MatchStatement = true
+
+	db = replayer.DB()
+	for _, statement := range []string{"DELETE FROM b", "DELETE FROM a"} {
+		if _, err := db.ExecContext(context.Background(), statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := replayer.Drift(); err != nil {
+		t.Fatal(err)
+	}
+}
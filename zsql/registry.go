@@ -0,0 +1,202 @@
+package zsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotRegistered  = errors.New("database not registered")
+	ErrRegistered     = errors.New("database already registered")
+	ErrRegistryClosed = errors.New("registry closed")
+)
+
+type (
+	RegistryConfig struct {
+		Driver    string
+		DSN       string
+		Connector driver.Connector
+
+		MaxOpenConns    int
+		MaxIdleConns    int
+		ConnMaxLifetime time.Duration
+		ConnMaxIdleTime time.Duration
+
+		// Decorators wrap session conn in order, so that statements in session transaction are decorated too
+		Decorators []func(Conn) Conn
+	}
+
+	RegistryStats struct {
+		sql.DBStats
+		DBs map[string]sql.DBStats
+	}
+
+	Registry struct {
+		mu      sync.Mutex
+		closed  bool
+		configs map[string]RegistryConfig
+		opened  map[string]*registryEntry
+	}
+
+	registryEntry struct {
+		db   *sql.DB
+		stmt *StmtCacheConn
+		conn Conn
+	}
+)
+
+func NewRegistry() *Registry {
+	return &Registry{configs: make(map[string]RegistryConfig), opened: make(map[string]*registryEntry)}
+}
+
+func (r *Registry) Register(name string, config RegistryConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrRegistryClosed
+	} else if _, exist := r.configs[name]; exist {
+		return fmt.Errorf("%w: %s", ErrRegistered, name)
+	}
+	r.configs[name] = config
+	return nil
+}
+
+func (config RegistryConfig) open() (db *sql.DB, err error) {
+	if config.Connector != nil {
+		db = sql.OpenDB(config.Connector)
+	} else if db, err = sql.Open(config.Driver, config.DSN); err != nil {
+		return
+	}
+	if config.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+	if config.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	}
+	return
+}
+
+func (r *Registry) entry(name string) (*registryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, ErrRegistryClosed
+	} else if entry, ok := r.opened[name]; ok {
+		return entry, nil
+	}
+
+	config, ok := r.configs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotRegistered, name)
+	}
+
+	db, err := config.open()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := NewStmtCacher(db)
+	conn := SessionConn(db, func(Conn) Conn { return stmt })
+	for _, decorate := range config.Decorators {
+		conn = decorate(conn)
+	}
+	entry := &registryEntry{db: db, stmt: stmt, conn: conn}
+	r.opened[name] = entry
+	return entry, nil
+}
+
+// DB returns database registered with name, opening it at first use.
+func (r *Registry) DB(name string) (*sql.DB, error) {
+	entry, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	return entry.db, nil
+}
+
+// Conn returns session conn of database on shared statement cache with decorators applied.
+func (r *Registry) Conn(name string) (Conn, error) {
+	entry, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	return entry.conn, nil
+}
+
+func (r *Registry) StmtCache(name string) (*StmtCacheConn, error) {
+	entry, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	return entry.stmt, nil
+}
+
+func (r *Registry) Names() (names []string) {
+	r.mu.Lock()
+	for name := range r.configs {
+		names = append(names, name)
+	}
+	r.mu.Unlock()
+	sort.Strings(names)
+	return
+}
+
+// Stats returns stats of opened databases and their sum.
+func (r *Registry) Stats() (stats RegistryStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats.DBs = make(map[string]sql.DBStats, len(r.opened))
+	for name, entry := range r.opened {
+		s := entry.db.Stats()
+		stats.DBs[name] = s
+		stats.MaxOpenConnections += s.MaxOpenConnections
+		stats.OpenConnections += s.OpenConnections
+		stats.InUse += s.InUse
+		stats.Idle += s.Idle
+		stats.WaitCount += s.WaitCount
+		stats.WaitDuration += s.WaitDuration
+		stats.MaxIdleClosed += s.MaxIdleClosed
+		stats.MaxIdleTimeClosed += s.MaxIdleTimeClosed
+		stats.MaxLifetimeClosed += s.MaxLifetimeClosed
+	}
+	return
+}
+
+// Close closes all statement caches before databases to release prepared statements on live connections.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	var errs []string
+	for name, entry := range r.opened {
+		if err := entry.stmt.Close(); err != nil {
+			errs = append(errs, name+": "+err.Error())
+		}
+	}
+	for name, entry := range r.opened {
+		if err := entry.db.Close(); err != nil {
+			errs = append(errs, name+": "+err.Error())
+		}
+	}
+	r.opened = nil
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(errs, ". "))
+}
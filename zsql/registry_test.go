@@ -0,0 +1,99 @@
+package zsql_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestRegistry(t *testing.T) {
+	capture := zsql.NewCapture(zsql.MySQL, nil)
+	registry := zsql.NewRegistry()
+
+	if err := registry.Register("main", zsql.RegistryConfig{
+		Connector:    capture.Connector(),
+		MaxOpenConns: 4,
+		Decorators:   []func(zsql.Conn) zsql.Conn{zsql.Commenter(zsql.TagRoute)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register("main", zsql.RegistryConfig{}); !errors.Is(err, zsql.ErrRegistered) {
+		t.Fatal(err)
+	}
+	if _, err := registry.Conn("unknown"); !errors.Is(err, zsql.ErrNotRegistered) {
+		t.Fatal(err)
+	}
+	if stats := registry.Stats(); len(stats.DBs) != 0 {
+		t.Fatal("opened before use")
+	}
+
+	conn, err := registry.Conn("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = conn.ExecContext(zsql.WithSQLTags(ctx, zsql.TagRoute, "/a"), "DELETE FROM test"); err != nil {
+		t.Fatal(err)
+	}
+	if captured := capture.Captured(); len(captured) != 1 || captured[0].Statement != "DELETE FROM test /*route='%2Fa'*/" {
+		t.Fatal(captured)
+	}
+
+	db, _ := registry.DB("main")
+	capture.Reset()
+	if err = zsql.WithSessionTx(zsql.WithSQLTags(ctx, zsql.TagRoute, "/b"), db, func(ctx context.Context) (err error) {
+		_, err = conn.ExecContext(ctx, "DELETE FROM test")
+		return
+	}); err != nil {
+		t.Fatal(err)
+	} else if captured := capture.Captured(); len(captured) != 3 || captured[1].Statement != "DELETE FROM test /*route='%2Fb'*/" {
+		t.Fatal(captured)
+	}
+
+	if stmt, _ := registry.StmtCache("main"); stmt == nil {
+		t.Fatal("no stmt cache")
+	}
+	if stats := registry.Stats(); len(stats.DBs) != 1 || stats.MaxOpenConnections != 4 {
+		t.Fatal(stats)
+	}
+
+	if err = registry.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = registry.DB("main"); !errors.Is(err, zsql.ErrRegistryClosed) {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryCloseConcurrent(t *testing.T) {
+	registry := zsql.NewRegistry()
+	if err := registry.Register("main", zsql.RegistryConfig{Connector: zsql.NewCapture(zsql.MySQL, nil).Connector()}); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := registry.Conn("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wg, started := sync.WaitGroup{}, sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		started.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if j == 10 {
+					started.Done()
+				}
+				_, _ = conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM test WHERE id = %d", i*1000+j))
+			}
+		}(i)
+	}
+	// close while statements are still being prepared
+	started.Wait()
+	_ = registry.Close()
+	wg.Wait()
+}
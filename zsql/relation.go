@@ -0,0 +1,190 @@
+package zsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+var (
+	DefaultChunkSize = 1000
+
+	ErrRelationKey = errors.New("relation key not in field mapping")
+)
+
+type (
+	Relation struct {
+		// create new child model for scanning
+		New func() Model
+		// column of child table refer to parent key
+		ForeignKey string
+		// column of parent table referred by children
+		ParentKey string
+		// fields of child model to select, foreign key would be appended if missing
+		Fields []string
+		// attach loaded child to parent, called once per matched pair
+		Attach func(parent, child Model)
+		// max size of keys in one IN condition, orm ChunkSize or DefaultChunkSize if zero
+		ChunkSize int
+		// nested relations loaded on children
+		Relations []Relation
+	}
+
+	modelSlice struct {
+		new    func() Model
+		models []Model
+	}
+)
+
+func (s *modelSlice) Iterate(f func(v interface{}, alloc bool) (next bool)) {
+	for i := 0; ; i++ {
+		if alloc := i >= len(s.models); !alloc {
+			if !f(s.models[i], alloc) {
+				return
+			}
+		} else if m := s.new(); f(m, alloc) {
+			s.models = append(s.models, m)
+		} else {
+			return
+		}
+	}
+}
+
+// key value normalized into driver value, so that keys of different go types match
+func indirectValue(ptr interface{}) (v interface{}, err error) {
+	if v, err = driver.DefaultParameterConverter.ConvertValue(ptr); err != nil {
+		return nil, fmt.Errorf("invalid key value: %w", err)
+	} else if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	return
+}
+
+func (orm Litorm) chunkSize(size int) int {
+	if size > 0 {
+		return size
+	} else if orm.ChunkSize > 0 {
+		return orm.ChunkSize
+	}
+	return DefaultChunkSize
+}
+
+func (orm Litorm) Preload(ctx context.Context, parents ModelIterator, relations ...Relation) (err error) {
+	for _, relation := range relations {
+		if err = orm.preload(ctx, parents, relation); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (orm Litorm) preload(ctx context.Context, parents ModelIterator, relation Relation) (err error) {
+	if relation.New == nil || relation.Attach == nil || len(relation.ForeignKey) == 0 || len(relation.ParentKey) == 0 {
+		return errors.New("invalid relation")
+	}
+
+	keys := make([]interface{}, 0)
+	groups := make(map[interface{}][]Model)
+	mapping := make(FieldMapping)
+	childMapping := make(FieldMapping)
+
+	parents.Iterate(func(v interface{}, alloc bool) (next bool) {
+		model, ok := v.(Model)
+		if alloc {
+			return
+		} else if !ok {
+			err = ErrInvalidModelsIterator
+			return
+		}
+		model.FieldMapping(mapping)
+		ptr, ok := mapping[relation.ParentKey]
+		if !ok {
+			err = fmt.Errorf("%w: %s of %s", ErrRelationKey, relation.ParentKey, model.TableName())
+			return
+		}
+		key, e := indirectValue(ptr)
+		if err = e; err != nil {
+			return
+		} else if key == nil {
+			return true
+		} else if _, exist := groups[key]; !exist {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], model)
+		return true
+	})
+
+	if err != nil || len(keys) == 0 {
+		return
+	}
+
+	fields := relation.Fields
+	if len(fields) > 0 {
+		fields = append(make([]string, 0, len(fields)+1), fields...)
+		if !containsString(fields, relation.ForeignKey) {
+			fields = append(fields, relation.ForeignKey)
+		}
+	}
+
+	children := &modelSlice{new: relation.New}
+	size := orm.chunkSize(relation.ChunkSize)
+
+	for offset := 0; offset < len(keys); offset += size {
+		chunk := keys[offset:]
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+
+		loaded := &modelSlice{new: relation.New}
+		condition := orm.builder()
+		condition.WriteString("WHERE ")
+		condition.writeIn(relation.ForeignKey, len(chunk))
+		ext := append([]interface{}{condition.String()}, chunk...)
+		if err = orm.Selects(ctx, loaded, fields, ext...); err != nil {
+			return
+		}
+
+		for _, child := range loaded.models {
+			child.FieldMapping(childMapping)
+			ptr, ok := childMapping[relation.ForeignKey]
+			if !ok {
+				return fmt.Errorf("%w: %s of %s", ErrRelationKey, relation.ForeignKey, child.TableName())
+			}
+			key, e := indirectValue(ptr)
+			if e != nil {
+				return e
+			}
+			for _, parent := range groups[key] {
+				relation.Attach(parent, child)
+			}
+		}
+		children.models = append(children.models, loaded.models...)
+	}
+
+	if len(relation.Relations) > 0 && len(children.models) > 0 {
+		err = orm.Preload(ctx, children, relation.Relations...)
+	}
+	return
+}
+
+func (bd *SqlBuilder) writeIn(field string, n int) {
+	bd.quote(field)
+	bd.WriteString(" IN (")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			bd.WriteRune(',')
+		}
+		bd.WriteRune('?')
+	}
+	bd.WriteRune(')')
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
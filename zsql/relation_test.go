@@ -0,0 +1,126 @@
+package zsql_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+type (
+	parentT struct {
+		ID       int64
+		Children []*childT
+	}
+
+	childT struct {
+		ID       int64
+		ParentID int32
+		Items    []*itemT
+	}
+
+	itemT struct{ ChildID int64 }
+
+	parentsT []*parentT
+)
+
+func (t *parentT) TableName() string { return "parent" }
+
+func (t *parentT) FieldMapping(dst map[string]interface{}) { dst["id"] = &t.ID }
+
+func (t *childT) TableName() string { return "child" }
+
+func (t *childT) FieldMapping(dst map[string]interface{}) {
+	dst["id"] = &t.ID
+	dst["parent_id"] = &t.ParentID
+}
+
+func (t *itemT) TableName() string { return "item" }
+
+func (t *itemT) FieldMapping(dst map[string]interface{}) { dst["child_id"] = &t.ChildID }
+
+func (s parentsT) Iterate(f func(v interface{}, alloc bool) (next bool)) {
+	for _, v := range s {
+		if !f(v, false) {
+			return
+		}
+	}
+}
+
+func TestPreloadAttach(t *testing.T) {
+	capture := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) (ret zsql.CaptureResult) {
+		switch {
+		case strings.Contains(statement, "FROM `child`"):
+			ret.Columns = []string{"id", "parent_id"}
+			for _, arg := range args {
+				id := arg.(int64)
+				ret.Rows = append(ret.Rows, []interface{}{id * 10, id}, []interface{}{id*10 + 1, id})
+			}
+		case strings.Contains(statement, "FROM `item`"):
+			ret.Columns = []string{"child_id"}
+			for _, arg := range args {
+				ret.Rows = append(ret.Rows, []interface{}{arg})
+			}
+		}
+		return
+	})
+
+	parents := parentsT{{ID: 1}, {ID: 2}, {ID: 3}}
+	err := zsql.Litorm{Conn: capture.DB(), ChunkSize: 2}.Preload(ctx, parents, zsql.Relation{
+		New:        func() zsql.Model { return &childT{} },
+		ForeignKey: "parent_id",
+		ParentKey:  "id",
+		Attach: func(parent, child zsql.Model) {
+			p := parent.(*parentT)
+			p.Children = append(p.Children, child.(*childT))
+		},
+		Relations: []zsql.Relation{{
+			New:        func() zsql.Model { return &itemT{} },
+			ForeignKey: "child_id",
+			ParentKey:  "id",
+			Attach: func(parent, child zsql.Model) {
+				c := parent.(*childT)
+				c.Items = append(c.Items, child.(*itemT))
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2 chunks of children, 6 children loaded in 3 chunks of items
+	if n := len(capture.Captured()); n != 5 {
+		t.Fatal(capture.Render())
+	}
+	for _, p := range parents {
+		if len(p.Children) != 2 {
+			t.Fatal(p.ID, p.Children)
+		}
+		for _, c := range p.Children {
+			if int64(c.ParentID) != p.ID || len(c.Items) != 1 || c.Items[0].ChildID != c.ID {
+				t.Fatal(p.ID, c)
+			}
+		}
+	}
+}
+
+func TestPreloadKeys(t *testing.T) {
+	capture := zsql.NewCapture(zsql.MySQL, func(statement string, args []interface{}) zsql.CaptureResult {
+		return zsql.CaptureResult{Columns: []string{"child_id"}, Rows: [][]interface{}{{int64(1)}}}
+	})
+	orm := zsql.Litorm{Conn: capture.DB()}
+
+	// parent key not mapped by parent, foreign key only mapped by parent
+	for _, keys := range [][2]string{{"child_id", "child_id"}, {"id", "id"}} {
+		err := orm.Preload(ctx, parentsT{{ID: 1}}, zsql.Relation{
+			New:        func() zsql.Model { return &itemT{} },
+			ForeignKey: keys[0],
+			ParentKey:  keys[1],
+			Attach:     func(parent, child zsql.Model) { t.Fatal(parent, child) },
+		})
+		if !errors.Is(err, zsql.ErrRelationKey) {
+			t.Fatal(keys, err)
+		}
+	}
+}
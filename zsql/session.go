@@ -3,7 +3,11 @@ package zsql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type (
@@ -17,13 +21,38 @@ type (
 	sessionTx struct {
 		Conn
 		sync.Mutex
-		commits []func(ctx context.Context)
+		commits   []func(ctx context.Context) error
+		rollbacks []func(ctx context.Context, cause error) error
+
+		statements int64
+		start      time.Time
+		stack      []uintptr
+	}
+
+	CallbackError struct {
+		Cause  error
+		Errors []error
 	}
 )
 
+func (e *CallbackError) Error() string {
+	errs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err.Error())
+	}
+	if msg := strings.Join(errs, ". "); e.Cause != nil {
+		return fmt.Sprintf("session callback error %s from error: %v", msg, e.Cause)
+	} else {
+		return "session callback error " + msg
+	}
+}
+
+func (e *CallbackError) Unwrap() error { return e.Cause }
+
 func (conn sessionConn) get(ctx context.Context) Conn {
-	if sc, ok := ctx.Value(sessionKey{DB: conn.db}).(Conn); ok {
-		return sc
+	if stx, ok := ctx.Value(sessionKey{DB: conn.db}).(*sessionTx); ok {
+		atomic.AddInt64(&stx.statements, 1)
+		return stx
 	}
 	return conn.conn
 }
@@ -44,13 +73,78 @@ func (conn sessionConn) PrepareContext(ctx context.Context, statement string) (s
 	return conn.get(ctx).PrepareContext(ctx, statement)
 }
 
-func (stx *sessionTx) commit(ctx context.Context) {
+func callback(fn func() error) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("callback panic: %v", e)
+		}
+	}()
+	return fn()
+}
+
+func (stx *sessionTx) onCommit(fns ...func(ctx context.Context)) {
+	stx.Lock()
+	defer stx.Unlock()
+	for i := range fns {
+		fn := fns[i]
+		stx.commits = append(stx.commits, func(ctx context.Context) error { fn(ctx); return nil })
+	}
+}
+
+func (stx *sessionTx) commit(ctx context.Context, ordered bool) (errs []error) {
+	if ordered {
+		for i := range stx.commits {
+			if err := callback(func() error { return stx.commits[i](ctx) }); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return
+	}
+
+	mu := sync.Mutex{}
 	wg := &sync.WaitGroup{}
 	wg.Add(len(stx.commits))
 	for i := range stx.commits {
-		go func(i int) { defer wg.Done(); stx.commits[i](ctx) }(i)
+		go func(i int) {
+			defer wg.Done()
+			if err := callback(func() error { return stx.commits[i](ctx) }); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(i)
 	}
 	wg.Wait()
+	return
+}
+
+func (stx *sessionTx) rollback(ctx context.Context, cause error) (errs []error) {
+	for i := range stx.rollbacks {
+		if err := callback(func() error { return stx.rollbacks[i](ctx, cause) }); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return
+}
+
+func (stx *sessionTx) callbacks(ctx context.Context, opt *txOption, cause error) []error {
+	stx.Lock()
+	defer stx.Unlock()
+	if cause == nil {
+		return stx.commit(ctx, opt.OrderedCommits)
+	}
+	return stx.rollback(ctx, cause)
+}
+
+func (opt *txOption) callbackError(cause error, errs []error) error {
+	if len(errs) == 0 {
+		return cause
+	} else if err := (&CallbackError{Cause: cause, Errors: errs}); opt.CallbackError != nil {
+		opt.CallbackError(err)
+		return cause
+	} else {
+		return err
+	}
 }
 
 func SessionConn(db DB, opts ...func(Conn) Conn) Conn {
@@ -61,18 +155,46 @@ func SessionConn(db DB, opts ...func(Conn) Conn) Conn {
 	return sessionConn{db: db, conn: conn}
 }
 
+func InSession(ctx context.Context, db DB) (ok bool) {
+	_, ok = ctx.Value(sessionKey{DB: db}).(*sessionTx)
+	return
+}
+
+func OnCommit(ctx context.Context, db DB, fn func(ctx context.Context) error) (ok bool) {
+	stx, ok := ctx.Value(sessionKey{DB: db}).(*sessionTx)
+	if ok {
+		stx.Lock()
+		stx.commits = append(stx.commits, fn)
+		stx.Unlock()
+	}
+	return
+}
+
+func OnRollback(ctx context.Context, db DB, fn func(ctx context.Context, cause error) error) (ok bool) {
+	stx, ok := ctx.Value(sessionKey{DB: db}).(*sessionTx)
+	if ok {
+		stx.Lock()
+		stx.rollbacks = append(stx.rollbacks, fn)
+		stx.Unlock()
+	}
+	return
+}
+
 func WithSessionTx(ctx context.Context, db DB, fn func(context.Context) error, onCommits ...func(ctx context.Context)) (err error) {
 	key := sessionKey{DB: db}
 	if stx, in := ctx.Value(key).(*sessionTx); in {
-		stx.Lock()
-		stx.commits = append(stx.commits, onCommits...)
-		stx.Unlock()
+		stx.onCommit(onCommits...)
 		return fn(ctx)
-	} else if err = WithTx(ctx, db, func(ctx context.Context, conn Conn) error {
-		stx = &sessionTx{commits: onCommits, Conn: conn}
-		return fn(context.WithValue(ctx, key, stx))
-	}); err == nil && len(stx.commits) > 0 {
-		stx.commit(ctx)
 	}
-	return
+
+	opt := loadTxOption(ctx)
+	stx := &sessionTx{}
+	stx.onCommit(onCommits...)
+	untrack := stx.track(opt)
+	err = WithTx(ctx, db, func(ctx context.Context, conn Conn) error {
+		stx.Conn = conn
+		return fn(context.WithValue(ctx, key, stx))
+	})
+	untrack()
+	return opt.callbackError(err, stx.callbacks(ctx, opt, err))
 }
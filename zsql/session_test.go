@@ -0,0 +1,72 @@
+package zsql_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestCaptureRollback(t *testing.T) {
+	db := zsql.NewCapture(zsql.MySQL, nil).DB()
+	cause := errors.New("cause")
+
+	err := zsql.WithSessionTx(ctx, db, func(ctx context.Context) error {
+		zsql.OnRollback(ctx, db, func(ctx context.Context, err error) error { panic(err) })
+		return cause
+	})
+
+	var cbErr *zsql.CallbackError
+	if !errors.Is(err, cause) || !errors.As(err, &cbErr) || len(cbErr.Errors) != 1 {
+		t.Fatal(err)
+	}
+}
+
+func TestOrderedCommits(t *testing.T) {
+	db := zsql.NewCapture(zsql.MySQL, nil).DB()
+	ctx := zsql.WithTxOptions(ctx, zsql.WithOrderedCommits(true))
+
+	var order []int
+	err := zsql.WithSessionTx(ctx, db, func(ctx context.Context) error {
+		for i := 0; i < 5; i++ {
+			i := i
+			zsql.OnCommit(ctx, db, func(ctx context.Context) error { order = append(order, i); return nil })
+		}
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(order, []int{0, 1, 2, 3, 4}) {
+		t.Fatal(err, order)
+	}
+}
+
+func TestCommitCallbackErrors(t *testing.T) {
+	db := zsql.NewCapture(zsql.MySQL, nil).DB()
+	failed := errors.New("failed")
+
+	run := func(ctx context.Context) (executed bool, err error) {
+		err = zsql.WithSessionTx(ctx, db, func(ctx context.Context) error {
+			zsql.OnCommit(ctx, db, func(ctx context.Context) error { panic("boom") })
+			zsql.OnCommit(ctx, db, func(ctx context.Context) error { return failed })
+			zsql.OnCommit(ctx, db, func(ctx context.Context) error { executed = true; return nil })
+			return nil
+		})
+		return
+	}
+
+	for _, ordered := range []bool{false, true} {
+		executed, err := run(zsql.WithTxOptions(ctx, zsql.WithOrderedCommits(ordered)))
+		var cbErr *zsql.CallbackError
+		if !executed || !errors.As(err, &cbErr) || len(cbErr.Errors) != 2 || cbErr.Cause != nil {
+			t.Fatal(ordered, executed, err)
+		}
+	}
+
+	var handled error
+	executed, err := run(zsql.WithTxOptions(ctx, zsql.WithCallbackError(func(err error) { handled = err })))
+	var cbErr *zsql.CallbackError
+	if err != nil || !executed || !errors.As(handled, &cbErr) || len(cbErr.Errors) != 2 {
+		t.Fatal(err, executed, handled)
+	}
+}
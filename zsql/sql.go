@@ -6,6 +6,7 @@ import (
 	"errors"
 	"sort"
 	"strings"
+	"sync/atomic"
 )
 
 var ErrInvalidModelsIterator = errors.New("invalid models iterator")
@@ -24,12 +25,26 @@ type (
 
 	FieldMapping map[string]interface{}
 
-	Litorm struct{ Conn }
+	Litorm struct {
+		Conn
+		Dialect   Dialect
+		ChunkSize int
+		NullSafe  bool
+		ReturnID  bool
+		Strict    bool
+	}
+
+	SqlBuilder struct {
+		strings.Builder
+		Dialect Dialect
+		Strict  bool
 
-	SqlBuilder struct{ strings.Builder }
+		allow FieldMapping
+		err   error
+	}
 )
 
-func (item modelItem) Iterate(f func(interface{}, bool) bool) { f(item, false) }
+func (item modelItem) Iterate(f func(interface{}, bool) bool) { f(item.Model, false) }
 
 func (mapping FieldMapping) MapFields(model Model, fp *[]string) {
 	model.FieldMapping(mapping)
@@ -46,9 +61,33 @@ func (mapping FieldMapping) MapFields(model Model, fp *[]string) {
 }
 
 func (mapping FieldMapping) MapValues(fields []string, ptr *[]interface{}) {
+	if atomic.LoadInt32(&convertersSize) == 0 {
+		for _, field := range fields {
+			*ptr = append(*ptr, mapping[field])
+		}
+		return
+	}
 	for _, field := range fields {
-		*ptr = append(*ptr, mapping[field])
+		*ptr = append(*ptr, convertPtr(mapping[field]))
+	}
+}
+
+func (orm Litorm) builder() *SqlBuilder { return &SqlBuilder{Dialect: orm.Dialect, Strict: orm.Strict} }
+
+func (orm Litorm) exec(ctx context.Context, bd *SqlBuilder, args []interface{}) (result sql.Result, err error) {
+	statement, args, err := bd.Rebind(args)
+	if err != nil {
+		return
 	}
+	return orm.ExecContext(ctx, statement, args...)
+}
+
+func (orm Litorm) query(ctx context.Context, bd *SqlBuilder, args []interface{}) (rows *sql.Rows, err error) {
+	statement, args, err := bd.Rebind(args)
+	if err != nil {
+		return
+	}
+	return orm.QueryContext(ctx, statement, args...)
 }
 
 func (orm Litorm) Insert(ctx context.Context, ignore bool, model Model, fields []string, ext ...interface{}) (result sql.Result, err error) {
@@ -56,17 +95,36 @@ func (orm Litorm) Insert(ctx context.Context, ignore bool, model Model, fields [
 }
 
 func (orm Litorm) Inserts(ctx context.Context, ignore bool, models ModelIterator, fields []string, ext ...interface{}) (result sql.Result, err error) {
-	statement := new(SqlBuilder)
+	statement := orm.builder()
 	if ext, err = statement.BuildInsert(models, ignore, fields, ext); err != nil {
 		return
+	} else if orm.ReturnID {
+		return orm.insertsID(ctx, statement, models, ext)
 	}
-	return orm.ExecContext(ctx, statement.String(), ext...)
+	return orm.exec(ctx, statement, ext)
 }
 
 func (orm Litorm) Update(ctx context.Context, model Model, fields []string, condition string, args ...interface{}) (result sql.Result, err error) {
-	statement := new(SqlBuilder)
+	tracked := tracking(model)
+	if tracked && len(fields) == 0 {
+		if fields = Changed(model); len(fields) == 0 {
+			return rowsResult{}, nil
+		}
+	}
+	statement := orm.builder()
 	args = statement.BuildUpdate(model, fields, condition, args)
-	return orm.ExecContext(ctx, statement.String(), args...)
+	if result, err = orm.exec(ctx, statement, args); err == nil && tracked {
+		mapping := make(FieldMapping, len(fields))
+		mapping.MapFields(model, &fields)
+		snapshot(model, mapping, fields, true)
+	}
+	return
+}
+
+func (orm Litorm) Delete(ctx context.Context, model Model, condition string, args ...interface{}) (result sql.Result, err error) {
+	statement := orm.builder()
+	args = statement.BuildDelete(model, condition, args)
+	return orm.exec(ctx, statement, args)
 }
 
 func (orm Litorm) Selects(ctx context.Context, models ModelIterator, fields []string, ext ...interface{}) (err error) {
@@ -92,19 +150,17 @@ func (orm Litorm) selects(ctx context.Context, models ModelIterator, fields []st
 		if model, ok := v.(Model); !ok {
 			err = ErrInvalidModelsIterator
 		} else if mapping.MapFields(model, &fields); rows == nil {
-			statement := new(SqlBuilder)
+			statement := orm.builder()
 			ext = statement.BuildSelect(model, fields, ext)
-			rows, err = orm.QueryContext(ctx, statement.String(), ext...)
+			rows, err = orm.query(ctx, statement, ext)
 			if ext = ext[:0]; err == nil && rows.Next() {
-				mapping.MapValues(fields, &ext)
-				err = rows.Scan(ext...)
+				err = orm.scan(rows, model, mapping, fields, &ext)
 			} else if err == nil {
 				err = sql.ErrNoRows
 			}
 			return err == nil
 		} else if ext = ext[:0]; rows.Next() {
-			mapping.MapValues(fields, &ext)
-			err = rows.Scan(ext...)
+			err = orm.scan(rows, model, mapping, fields, &ext)
 			return err == nil
 		}
 		return false
@@ -112,9 +168,25 @@ func (orm Litorm) selects(ctx context.Context, models ModelIterator, fields []st
 	return
 }
 
+func (orm Litorm) scan(rows *sql.Rows, model Model, mapping FieldMapping, fields []string, dst *[]interface{}) (err error) {
+	if mapping.MapValues(fields, dst); !orm.NullSafe {
+		err = rows.Scan(*dst...)
+	} else {
+		nulls := wrapNulls(fields, *dst)
+		if err = rows.Scan(*dst...); err == nil {
+			nulls.assign(model)
+		}
+	}
+	if err == nil {
+		snapshot(model, mapping, fields, false)
+	}
+	return
+}
+
 func (bd *SqlBuilder) BuildUpdate(model Model, fields []string, ext string, xargs []interface{}) (args []interface{}) {
 	mapping := make(FieldMapping, len(fields))
 	mapping.MapFields(model, &fields)
+	bd.allow = mapping
 	bd.WriteString("UPDATE ")
 	bd.WriteTable(model.TableName())
 	bd.WriteString(" SET ")
@@ -127,15 +199,30 @@ func (bd *SqlBuilder) BuildUpdate(model Model, fields []string, ext string, xarg
 	return
 }
 
+func (bd *SqlBuilder) BuildDelete(model Model, ext string, xargs []interface{}) (args []interface{}) {
+	bd.WriteString("DELETE FROM ")
+	bd.WriteTable(model.TableName())
+	if len(ext) > 0 {
+		bd.WriteRune(' ')
+		bd.WriteString(ext)
+		args = append(args, xargs...)
+	}
+	return
+}
+
 func (bd *SqlBuilder) BuildInsert(models ModelIterator, ignore bool, fields []string, ext []interface{}) (args []interface{}, err error) {
 	mapping := make(FieldMapping, len(fields))
 	if models.Iterate(func(v interface{}, alloc bool) (next bool) {
 		if model, ok := v.(Model); alloc || !ok {
 			return
 		} else if mapping.MapFields(model, &fields); bd.Len() == 0 {
-			if ignore {
+			bd.allow = mapping
+			switch {
+			case ignore && bd.Dialect == MySQL:
 				bd.WriteString("INSERT IGNORE INTO ")
-			} else {
+			case ignore && bd.Dialect == SQLite:
+				bd.WriteString("INSERT OR IGNORE INTO ")
+			default:
 				bd.WriteString("INSERT INTO ")
 			}
 			bd.WriteTable(model.TableName())
@@ -151,14 +238,25 @@ func (bd *SqlBuilder) BuildInsert(models ModelIterator, ignore bool, fields []st
 		return true
 	}); bd.Len() == 0 {
 		return nil, ErrInvalidModelsIterator
+	} else if ignore && bd.Dialect == PostgreSQL {
+		bd.WriteString(" ON CONFLICT DO NOTHING")
 	}
 	bd.WriteExtArgs(ext, &args)
 	return
 }
 
-func (bd *SqlBuilder) quote(v string) { bd.WriteRune('`'); bd.WriteString(v); bd.WriteRune('`') }
+func (bd *SqlBuilder) quote(v string) {
+	q := string(bd.Dialect.QuoteRune())
+	bd.WriteString(q)
+	bd.WriteString(strings.ReplaceAll(v, q, q+q))
+	bd.WriteString(q)
+}
 
 func (bd *SqlBuilder) BuildSelect(model Model, fields []string, ext []interface{}) (args []interface{}) {
+	if bd.Strict {
+		bd.allow = make(FieldMapping, len(fields))
+		model.FieldMapping(bd.allow)
+	}
 	bd.WriteString("SELECT ")
 	bd.WriteFields(fields, true, "", ",")
 	bd.WriteString(" FROM ")
@@ -167,14 +265,22 @@ func (bd *SqlBuilder) BuildSelect(model Model, fields []string, ext []interface{
 	return
 }
 
-func (bd *SqlBuilder) WriteTable(table string) { bd.quote(table) }
+func (bd *SqlBuilder) WriteTable(table string) {
+	if bd.Strict {
+		bd.writeIdentifier(table)
+	} else {
+		bd.quote(table)
+	}
+}
 
 func (bd *SqlBuilder) WriteFields(fields []string, name bool, suffix, sep string) {
 	for i, field := range fields {
 		if len(field) == 0 {
 			continue
+		} else if name && bd.Strict {
+			bd.writeStrictField(field)
 		} else if name {
-			if !strings.ContainsAny(field, "`(,") {
+			if !strings.ContainsAny(field, "(,"+string(bd.Dialect.QuoteRune())) {
 				bd.quote(field)
 			} else {
 				bd.WriteString(field)
@@ -21,9 +21,15 @@ func NewStmtCacher(conn Conn) *StmtCacheConn {
 	return &StmtCacheConn{Conn: conn}
 }
 
+// Close closes cached statements, statements prepared after are cached again.
 func (sc *StmtCacheConn) Close() error {
+	sc.mu.Lock()
+	cache := sc.cache
+	sc.cache = nil
+	sc.mu.Unlock()
+
 	var errs []string
-	for _, cached := range sc.cache {
+	for _, cached := range cache {
 		if e := cached.Close(); e != nil {
 			errs = append(errs, e.Error())
 		}
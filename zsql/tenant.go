@@ -0,0 +1,258 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoTenant     = errors.New("tenant not found in context")
+	ErrTenantColumn = errors.New("tenant column not found in model")
+
+	tenantClauseKeywords = []string{
+		"GROUP BY", "HAVING", "WINDOW", "ORDER BY", "LIMIT", "OFFSET", "FETCH",
+		"FOR UPDATE", "FOR SHARE", "FOR NO KEY UPDATE", "LOCK IN SHARE MODE",
+		"UNION", "INTERSECT", "EXCEPT", "RETURNING", "ON DUPLICATE", "ON CONFLICT",
+	}
+)
+
+// TenantOrm scopes statements by tenant column of context.
+// Underlying Litorm is unexported so that unscoped methods and raw statements are unreachable.
+type TenantOrm struct {
+	orm    Litorm
+	Column string
+}
+
+func NewTenantOrm(orm Litorm, column string) TenantOrm { return TenantOrm{orm: orm, Column: column} }
+
+func WithTenant(ctx context.Context, tenant interface{}) context.Context {
+	return context.WithValue(ctx, contextKeyTenant, tenant)
+}
+
+func TenantFrom(ctx context.Context) (tenant interface{}, ok bool) {
+	tenant = ctx.Value(contextKeyTenant)
+	return tenant, tenant != nil
+}
+
+// CrossTenant marks context to skip tenant scoping explicitly for admin operations.
+func CrossTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyCrossTenant, true)
+}
+
+func (orm TenantOrm) tenant(ctx context.Context) (tenant interface{}, cross bool, err error) {
+	if cross, _ = ctx.Value(contextKeyCrossTenant).(bool); cross {
+		return
+	} else if tenant, ok := TenantFrom(ctx); ok {
+		return tenant, false, nil
+	}
+	return nil, false, ErrNoTenant
+}
+
+func isWordByte(c byte) bool { return isIdentRune(c, false) || c == '$' }
+
+// find index of first top level clause keyword outside quotes and parentheses
+func (d Dialect) clauseIndex(condition string, keywords []string) int {
+	depth := 0
+	upper := strings.ToUpper(condition)
+	for i := 0; i < len(condition); i++ {
+		switch c := condition[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(condition) && condition[i] != c; i++ {
+				if condition[i] == '\\' && c == '\'' && d == MySQL {
+					i++
+				}
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && (i == 0 || !isWordByte(condition[i-1])):
+			for _, keyword := range keywords {
+				if end := i + len(keyword); strings.HasPrefix(upper[i:], keyword) &&
+					(end == len(condition) || !isWordByte(condition[end])) {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+func (orm TenantOrm) scope(condition string) string {
+	bd := orm.orm.builder()
+	bd.WriteString("WHERE ")
+	bd.WriteFields([]string{orm.Column}, true, " = ?", "")
+
+	trimmed := strings.TrimSpace(orm.shiftNumbered(condition))
+	if len(trimmed) > 5 && strings.EqualFold(trimmed[:5], "WHERE") && !isWordByte(trimmed[5]) {
+		predicate, tail := trimmed[5:], ""
+		if i := orm.orm.Dialect.clauseIndex(predicate, tenantClauseKeywords); i >= 0 {
+			predicate, tail = predicate[:i], predicate[i:]
+		}
+		bd.WriteString(" AND (")
+		bd.WriteString(strings.TrimSpace(predicate))
+		bd.WriteRune(')')
+		condition = tail
+	}
+
+	if condition = strings.TrimSpace(condition); len(condition) > 0 {
+		bd.WriteRune(' ')
+		bd.WriteString(condition)
+	}
+	return bd.String()
+}
+
+// shift numbered placeholders of condition by one for tenant arg prepended to args
+func (orm TenantOrm) shiftNumbered(condition string) string {
+	d := orm.orm.Dialect
+	if d != PostgreSQL {
+		return condition
+	}
+	bd, last := new(strings.Builder), 0
+	_ = d.walkPlaceholders(condition, func(start, end int, name string) error {
+		if len(name) > 0 && name[0] == '$' {
+			n, _ := strconv.Atoi(name[1:])
+			bd.WriteString(condition[last:start])
+			bd.WriteString("$" + strconv.Itoa(n+1))
+			last = end
+		}
+		return nil
+	})
+	bd.WriteString(condition[last:])
+	return bd.String()
+}
+
+func (orm TenantOrm) scopeExt(tenant interface{}, ext []interface{}) []interface{} {
+	condition, args := "", ext
+	if len(ext) > 0 {
+		if expr, ok := ext[0].(string); ok {
+			condition, args = expr, ext[1:]
+		}
+	}
+	return append([]interface{}{orm.scope(condition), tenant}, args...)
+}
+
+func (orm TenantOrm) Select(ctx context.Context, model Model, fields []string, ext ...interface{}) (err error) {
+	tenant, cross, err := orm.tenant(ctx)
+	if err != nil {
+		return
+	} else if !cross {
+		ext = orm.scopeExt(tenant, ext)
+	}
+	return orm.orm.Select(ctx, model, fields, ext...)
+}
+
+func (orm TenantOrm) Selects(ctx context.Context, models ModelIterator, fields []string, ext ...interface{}) (err error) {
+	tenant, cross, err := orm.tenant(ctx)
+	if err != nil {
+		return
+	} else if !cross {
+		ext = orm.scopeExt(tenant, ext)
+	}
+	return orm.orm.Selects(ctx, models, fields, ext...)
+}
+
+func (orm TenantOrm) Update(ctx context.Context, model Model, fields []string, condition string, args ...interface{}) (result sql.Result, err error) {
+	tenant, cross, err := orm.tenant(ctx)
+	if err != nil {
+		return
+	} else if !cross {
+		condition, args = orm.scope(condition), append([]interface{}{tenant}, args...)
+	}
+	return orm.orm.Update(ctx, model, fields, condition, args...)
+}
+
+func (orm TenantOrm) Delete(ctx context.Context, model Model, condition string, args ...interface{}) (result sql.Result, err error) {
+	tenant, cross, err := orm.tenant(ctx)
+	if err != nil {
+		return
+	} else if !cross {
+		condition, args = orm.scope(condition), append([]interface{}{tenant}, args...)
+	}
+	return orm.orm.Delete(ctx, model, condition, args...)
+}
+
+func (orm TenantOrm) Insert(ctx context.Context, ignore bool, model Model, fields []string, ext ...interface{}) (result sql.Result, err error) {
+	return orm.Inserts(ctx, ignore, modelItem{Model: model}, fields, ext...)
+}
+
+func (orm TenantOrm) Inserts(ctx context.Context, ignore bool, models ModelIterator, fields []string, ext ...interface{}) (result sql.Result, err error) {
+	tenant, cross, err := orm.tenant(ctx)
+	if err != nil {
+		return
+	} else if !cross {
+		if err = orm.assign(models, tenant); err != nil {
+			return
+		} else if len(fields) > 0 && !containsString(fields, orm.Column) {
+			fields = append(append(make([]string, 0, len(fields)+1), fields...), orm.Column)
+		}
+	}
+	return orm.orm.Inserts(ctx, ignore, models, fields, ext...)
+}
+
+func (orm TenantOrm) assign(models ModelIterator, tenant interface{}) (err error) {
+	mapping := make(FieldMapping)
+	value := reflect.ValueOf(tenant)
+	models.Iterate(func(v interface{}, alloc bool) (next bool) {
+		model, ok := v.(Model)
+		if alloc || !ok {
+			return
+		}
+		model.FieldMapping(mapping)
+		dst := reflect.ValueOf(mapping[orm.Column])
+		if dst.Kind() != reflect.Ptr || dst.IsNil() {
+			err = ErrTenantColumn
+		} else if !value.Type().ConvertibleTo(dst.Elem().Type()) {
+			err = fmt.Errorf("invalid tenant type %s for %s", value.Type(), dst.Elem().Type())
+		} else {
+			dst.Elem().Set(value.Convert(dst.Elem().Type()))
+		}
+		return err == nil
+	})
+	return
+}
+
+func (orm TenantOrm) Get(ctx context.Context, model Model, fields []string) (err error) {
+	condition, args, err := orm.orm.keyCondition(model)
+	if err != nil {
+		return
+	}
+	return orm.Select(ctx, model, fields, append([]interface{}{condition}, args...)...)
+}
+
+func (orm TenantOrm) DeleteByKey(ctx context.Context, model Model) (result sql.Result, err error) {
+	condition, args, err := orm.orm.keyCondition(model)
+	if err != nil {
+		return
+	}
+	return orm.Delete(ctx, model, condition, args...)
+}
+
+func (orm TenantOrm) query(ctx context.Context, q Query) (Query, error) {
+	tenant, cross, err := orm.tenant(ctx)
+	if err != nil || cross {
+		return q, err
+	}
+	bd := orm.orm.builder()
+	bd.WriteFields([]string{orm.Column}, true, " = ?", "")
+	return q.Where(bd.String(), tenant), nil
+}
+
+func (orm TenantOrm) Find(ctx context.Context, models ModelIterator, q Query) (err error) {
+	if q, err = orm.query(ctx, q); err != nil {
+		return
+	}
+	return orm.orm.Find(ctx, models, q)
+}
+
+func (orm TenantOrm) FindOne(ctx context.Context, model Model, q Query) (err error) {
+	if q, err = orm.query(ctx, q); err != nil {
+		return
+	}
+	return orm.orm.FindOne(ctx, model, q)
+}
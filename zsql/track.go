@@ -0,0 +1,88 @@
+package zsql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type TxInfo struct {
+	Start      time.Time
+	Duration   time.Duration
+	Statements int64
+	Stack      string
+}
+
+// registry of open session transactions
+var openTxs sync.Map
+
+func (opt *txOption) timeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if opt.Timeout > 0 {
+		return context.WithTimeout(ctx, opt.Timeout)
+	}
+	return ctx, func() {}
+}
+
+func (stx *sessionTx) track(opt *txOption) (untrack func()) {
+	stx.start = time.Now()
+	stx.stack = make([]uintptr, 32)
+	stx.stack = stx.stack[:runtime.Callers(3, stx.stack)]
+	openTxs.Store(stx, struct{}{})
+
+	var timer *time.Timer
+	if opt.SlowThreshold > 0 && opt.SlowTx != nil {
+		timer = time.AfterFunc(opt.SlowThreshold, func() { opt.SlowTx(stx.info()) })
+	}
+	return func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		openTxs.Delete(stx)
+	}
+}
+
+func (stx *sessionTx) info() TxInfo {
+	bd := new(strings.Builder)
+	frames := runtime.CallersFrames(stx.stack)
+	for {
+		frame, more := frames.Next()
+		if len(frame.Function) > 0 {
+			fmt.Fprintf(bd, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return TxInfo{
+		Start:      stx.start,
+		Duration:   time.Since(stx.start),
+		Statements: atomic.LoadInt64(&stx.statements),
+		Stack:      bd.String(),
+	}
+}
+
+// OpenTxs returns info of session transactions still open, longest first.
+func OpenTxs() (infos []TxInfo) {
+	openTxs.Range(func(key, _ interface{}) bool {
+		infos = append(infos, key.(*sessionTx).info())
+		return true
+	})
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Start.Before(infos[j].Start) })
+	return
+}
+
+func DumpOpenTxs(w io.Writer) (err error) {
+	for _, info := range OpenTxs() {
+		if _, err = fmt.Fprintf(w, "tx open for %s since %s with %d statements\n%s\n",
+			info.Duration, info.Start.Format(time.RFC3339Nano), info.Statements, info.Stack); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package zsql_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zing/gozz-kit/zsql"
+)
+
+func TestTxTimeout(t *testing.T) {
+	db := zsql.NewCapture(zsql.MySQL, nil).DB()
+	ctx := zsql.WithTxOptions(ctx, zsql.WithTimeout(time.Millisecond*10))
+
+	err := zsql.WithSessionTx(ctx, db, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenTxs(t *testing.T) {
+	db := zsql.NewCapture(zsql.MySQL, nil).DB()
+	conn := zsql.SessionConn(db)
+	slow := make(chan zsql.TxInfo, 1)
+	ctx := zsql.WithTxOptions(ctx,
+		zsql.WithSlowThreshold(time.Millisecond),
+		zsql.WithSlowTx(func(info zsql.TxInfo) { slow <- info }),
+	)
+
+	err := zsql.WithSessionTx(ctx, db, func(ctx context.Context) error {
+		if _, err := conn.ExecContext(ctx, "DELETE FROM test"); err != nil {
+			return err
+		}
+		if infos := zsql.OpenTxs(); len(infos) != 1 || infos[0].Statements != 1 {
+			t.Fatal(infos)
+		}
+		if info := <-slow; !strings.Contains(info.Stack, "TestOpenTxs") {
+			t.Fatal(info.Stack)
+		}
+		buf := new(bytes.Buffer)
+		if err := zsql.DumpOpenTxs(buf); err != nil || !strings.Contains(buf.String(), "with 1 statements") {
+			t.Fatal(buf.String(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if infos := zsql.OpenTxs(); len(infos) != 0 {
+		t.Fatal(infos)
+	}
+}
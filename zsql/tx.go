@@ -5,20 +5,33 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"time"
 )
 
 type contextKey int
 
 const (
 	contextKeyTxOption contextKey = iota + 1
+	contextKeyActor
+	contextKeyTenant
+	contextKeyCrossTenant
+	contextKeySQLTags
 )
 
 //go:generate gozz run -p "option" ./
 // +zz:option
 type txOption struct {
-	SqlTxOptions *sql.TxOptions
-	Rollback     func(rollback func() error, cause error) error
-	Recovery     func(exception interface{}) error
+	SqlTxOptions   *sql.TxOptions
+	Rollback       func(rollback func() error, cause error) error
+	Recovery       func(exception interface{}) error
+	OrderedCommits bool
+	CallbackError  func(err error)
+	Compensation   func(ctx context.Context, err *PartialCommitError) error
+	// maximum duration of transaction before its context canceled
+	Timeout time.Duration
+	// duration of session transaction to report with SlowTx while still open
+	SlowThreshold time.Duration
+	SlowTx        func(info TxInfo)
 }
 
 type txOptions = []func(option *txOption)
@@ -30,11 +43,40 @@ func WithTxOptions(ctx context.Context, opts ...func(*txOption)) context.Context
 	return context.WithValue(ctx, contextKeyTxOption, &opts)
 }
 
-func WithTx(ctx context.Context, db DB, fn func(context.Context, Conn) error) (err error) {
-	opt := &txOption{}
+func loadTxOption(ctx context.Context) (opt *txOption) {
+	opt = &txOption{}
 	if options, ok := ctx.Value(contextKeyTxOption).(*txOptions); ok {
 		opt.applyOptions(*options...)
 	}
+	return
+}
+
+func (opt *txOption) recover(exception interface{}) error {
+	if opt.Recovery != nil {
+		return opt.Recovery(exception)
+	}
+	return fmt.Errorf("%v", exception)
+}
+
+func (opt *txOption) rollback(tx driver.Tx, err error) error {
+	if opt.Rollback != nil {
+		return opt.Rollback(tx.Rollback, err)
+	}
+
+	switch err {
+	case driver.ErrBadConn, context.Canceled:
+	default:
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("rollback error %v from error: %w", rerr, err)
+		}
+	}
+	return err
+}
+
+func WithTx(ctx context.Context, db DB, fn func(context.Context, Conn) error) (err error) {
+	opt := loadTxOption(ctx)
+	ctx, cancel := opt.timeout(ctx)
+	defer cancel()
 
 	tx, err := db.BeginTx(ctx, opt.SqlTxOptions)
 	if err != nil {
@@ -43,29 +85,13 @@ func WithTx(ctx context.Context, db DB, fn func(context.Context, Conn) error) (e
 
 	defer func() {
 		if e := recover(); e != nil {
-			if opt.Recovery != nil {
-				err = opt.Recovery(e)
-			} else {
-				err = fmt.Errorf("%v", e)
-			}
+			err = opt.recover(e)
 		}
 
 		if err == nil {
 			err = tx.Commit()
-			return
-		}
-
-		if opt.Rollback != nil {
-			err = opt.Rollback(tx.Rollback, err)
-			return
-		}
-
-		switch err {
-		case driver.ErrBadConn, context.Canceled:
-		default:
-			if rerr := tx.Rollback(); rerr != nil {
-				err = fmt.Errorf("rollback error %v from error: %w", rerr, err)
-			}
+		} else {
+			err = opt.rollback(tx, err)
 		}
 	}()
 	return fn(ctx, tx)
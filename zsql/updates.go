@@ -0,0 +1,219 @@
+package zsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type updateRow struct{ keys, values []interface{} }
+
+// MaxPlaceholders is max count of args in one statement, chunks of Updates are limited by it.
+var MaxPlaceholders = 65535
+
+// Updates updates fields of models matched by key fields in chunked statements.
+// All mapped fields except key fields are updated if fields is empty.
+func (orm Litorm) Updates(ctx context.Context, models ModelIterator, keyFields, fields []string) (result sql.Result, err error) {
+	if len(keyFields) == 0 {
+		return nil, errors.New("missing update key fields")
+	}
+
+	var rows []updateRow
+	var model Model
+	mapping := make(FieldMapping)
+	models.Iterate(func(v interface{}, alloc bool) (next bool) {
+		m, ok := v.(Model)
+		if alloc || !ok {
+			return
+		} else if model == nil {
+			model = m
+			if len(fields) == 0 {
+				mapping.MapFields(m, &fields)
+				fields = excludeStrings(fields, keyFields)
+			} else {
+				m.FieldMapping(mapping)
+			}
+		} else {
+			m.FieldMapping(mapping)
+		}
+		row := updateRow{}
+		mapping.MapValues(keyFields, &row.keys)
+		mapping.MapValues(fields, &row.values)
+		rows = append(rows, row)
+		return true
+	})
+
+	if model == nil {
+		return nil, ErrInvalidModelsIterator
+	} else if len(fields) == 0 {
+		return rowsResult{}, nil
+	}
+
+	rowArgs := len(keyFields) + len(fields)
+	if orm.Dialect != PostgreSQL {
+		rowArgs += len(fields) * len(keyFields)
+	}
+	size := orm.chunkSize(0)
+	if n := MaxPlaceholders / rowArgs; n < size {
+		if size = n; size < 1 {
+			size = 1
+		}
+	}
+
+	affected := int64(0)
+	for offset := 0; offset < len(rows); offset += size {
+		chunk := rows[offset:]
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+
+		statement := orm.builder()
+		statement.allow = mapping
+		var args []interface{}
+		if orm.Dialect == PostgreSQL {
+			args = statement.buildUpdatesFrom(model.TableName(), keyFields, fields, chunk)
+		} else {
+			args = statement.buildUpdatesCase(model.TableName(), keyFields, fields, chunk)
+		}
+
+		if result, err = orm.exec(ctx, statement, args); err != nil {
+			return
+		} else if n, e := result.RowsAffected(); e == nil {
+			affected += n
+		}
+	}
+	return rowsResult{rowsAffected: affected}, nil
+}
+
+func excludeStrings(list, exclude []string) []string {
+	ret := make([]string, 0, len(list))
+	for _, v := range list {
+		if !containsString(exclude, v) {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func (bd *SqlBuilder) writeKeysMatch(keys []string) {
+	for i, key := range keys {
+		if i > 0 {
+			bd.WriteString(" AND ")
+		}
+		bd.WriteFields([]string{key}, true, " = ?", "")
+	}
+}
+
+// build UPDATE with CASE expression per field and IN / OR condition on keys.
+func (bd *SqlBuilder) buildUpdatesCase(table string, keys, fields []string, rows []updateRow) (args []interface{}) {
+	bd.WriteString("UPDATE ")
+	bd.WriteTable(table)
+	bd.WriteString(" SET ")
+	for i, field := range fields {
+		if i > 0 {
+			bd.WriteRune(',')
+		}
+		bd.WriteFields([]string{field}, true, " = CASE", "")
+		if len(keys) == 1 {
+			bd.WriteRune(' ')
+			bd.WriteFields(keys, true, "", "")
+		}
+		for _, row := range rows {
+			if len(keys) == 1 {
+				bd.WriteString(" WHEN ? THEN ?")
+			} else {
+				bd.WriteString(" WHEN ")
+				bd.writeKeysMatch(keys)
+				bd.WriteString(" THEN ?")
+			}
+			args = append(append(args, row.keys...), row.values[i])
+		}
+		bd.WriteString(" END")
+	}
+
+	bd.WriteString(" WHERE ")
+	if len(keys) == 1 {
+		bd.WriteFields(keys, true, "", "")
+		bd.WriteString(" IN (")
+		for i, row := range rows {
+			if i > 0 {
+				bd.WriteRune(',')
+			}
+			bd.WriteRune('?')
+			args = append(args, row.keys...)
+		}
+		bd.WriteRune(')')
+		return
+	}
+	for i, row := range rows {
+		if i > 0 {
+			bd.WriteString(" OR ")
+		}
+		bd.WriteRune('(')
+		bd.writeKeysMatch(keys)
+		bd.WriteRune(')')
+		args = append(args, row.keys...)
+	}
+	return
+}
+
+// build PostgreSQL UPDATE FROM VALUES list joined on keys.
+// VALUES starts with row of typed nulls of table columns, so that placeholders of following rows
+// are resolved as column types. Null keys of the typed row never match the join condition.
+func (bd *SqlBuilder) buildUpdatesFrom(table string, keys, fields []string, rows []updateRow) (args []interface{}) {
+	const alias = "_v"
+	bd.WriteString("UPDATE ")
+	bd.WriteTable(table)
+	bd.WriteString(" SET ")
+	for i, field := range fields {
+		if i > 0 {
+			bd.WriteRune(',')
+		}
+		bd.WriteFields([]string{field}, true, " = ", "")
+		bd.quote(alias)
+		bd.WriteRune('.')
+		bd.WriteFields([]string{field}, true, "", "")
+	}
+
+	columns := append(append(make([]string, 0, len(keys)+len(fields)), keys...), fields...)
+	bd.WriteString(" FROM (VALUES (")
+	for i, column := range columns {
+		if i > 0 {
+			bd.WriteRune(',')
+		}
+		bd.WriteString("(NULL::")
+		bd.WriteTable(table)
+		bd.WriteString(").")
+		bd.WriteFields([]string{column}, true, "", "")
+	}
+	bd.WriteRune(')')
+	for _, row := range rows {
+		bd.WriteString(",(")
+		values := append(append([]interface{}{}, row.keys...), row.values...)
+		for j := range values {
+			if j > 0 {
+				bd.WriteRune(',')
+			}
+			bd.WriteRune('?')
+		}
+		bd.WriteRune(')')
+		args = append(args, values...)
+	}
+	bd.WriteString(") AS ")
+	bd.quote(alias)
+	bd.WriteRune('(')
+	bd.WriteFields(columns, true, "", ",")
+	bd.WriteString(") WHERE ")
+	for i, key := range keys {
+		if i > 0 {
+			bd.WriteString(" AND ")
+		}
+		bd.WriteTable(table)
+		bd.WriteRune('.')
+		bd.WriteFields([]string{key}, true, " = ", "")
+		bd.quote(alias)
+		bd.WriteRune('.')
+		bd.WriteFields([]string{key}, true, "", "")
+	}
+	return
+}
@@ -3,7 +3,9 @@
 package zsql
 
 import (
+	"context"
 	"database/sql"
+	"time"
 )
 
 // apply functional options for txOption
@@ -24,3 +26,29 @@ func WithRollback(v func(rollback func() error, cause error) error) func(*txOpti
 func WithRecovery(v func(exception interface{}) error) func(*txOption) {
 	return func(o *txOption) { o.Recovery = v }
 }
+
+func WithOrderedCommits(v bool) func(*txOption) {
+	return func(o *txOption) { o.OrderedCommits = v }
+}
+
+func WithCallbackError(v func(err error)) func(*txOption) {
+	return func(o *txOption) { o.CallbackError = v }
+}
+
+func WithCompensation(v func(ctx context.Context, err *PartialCommitError) error) func(*txOption) {
+	return func(o *txOption) { o.Compensation = v }
+}
+
+// maximum duration of transaction before its context canceled
+func WithTimeout(v time.Duration) func(*txOption) {
+	return func(o *txOption) { o.Timeout = v }
+}
+
+// duration of session transaction to report with SlowTx while still open
+func WithSlowThreshold(v time.Duration) func(*txOption) {
+	return func(o *txOption) { o.SlowThreshold = v }
+}
+
+func WithSlowTx(v func(info TxInfo)) func(*txOption) {
+	return func(o *txOption) { o.SlowTx = v }
+}